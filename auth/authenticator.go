@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	AuthorizationMetadataKey = "authorization" // Bearer <jwt>
+	APIKeyMetadataKey        = "x-api-key"
+)
+
+var ErrInvalidCredentials = errors.New("credentials are invalid")
+
+var hmacMethods = []string{"HS256", "HS384", "HS512"}
+var keyMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+type apiKey struct {
+	hash     [32]byte
+	identity Identity
+}
+
+// Authenticator validates the credentials of metadata, it is created once in start of application
+type Authenticator struct {
+	config  Config
+	jwks    map[string]interface{} // public keys by kid
+	apiKeys []apiKey
+	methods []string
+}
+
+func New(cfg Config) (*Authenticator, error) {
+	a := &Authenticator{config: cfg, jwks: map[string]interface{}{}}
+
+	if cfg.JWTSecret != "" {
+		a.methods = append(a.methods, hmacMethods...)
+	}
+
+	if cfg.JWKSFile != "" {
+		jwks, err := LoadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		a.jwks = jwks
+		a.methods = append(a.methods, keyMethods...)
+	}
+
+	for _, key := range cfg.APIKeys {
+		a.apiKeys = append(a.apiKeys, apiKey{
+			hash:     sha256.Sum256([]byte(key.Key)),
+			identity: Identity{Subject: key.Subject, Roles: key.Roles, Method: MethodAPIKey},
+		})
+	}
+
+	return a, nil
+}
+
+// Authenticate returns the identity of metadata, false when there is no credential.
+// Bearer token is checked before api key.
+func (a *Authenticator) Authenticate(md metadata.MD) (Identity, bool, error) {
+	if values := md.Get(AuthorizationMetadataKey); len(values) > 0 && values[0] != "" {
+		token := values[0]
+		if len(token) < 7 || !strings.EqualFold(token[:7], "bearer ") {
+			return Identity{}, true, errors.New("authorization must be Bearer token")
+		}
+		identity, err := a.parseToken(strings.TrimSpace(token[7:]))
+		return identity, true, err
+	}
+
+	if values := md.Get(APIKeyMetadataKey); len(values) > 0 && values[0] != "" {
+		identity, err := a.checkAPIKey(values[0])
+		return identity, true, err
+	}
+
+	return Identity{}, false, nil
+}
+
+func (a *Authenticator) parseToken(token string) (Identity, error) {
+	if len(a.methods) == 0 {
+		return Identity{}, errors.New("tokens are not accepted")
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, a.keyFunc, jwt.WithValidMethods(a.methods))
+	if err != nil {
+		return Identity{}, errors.New("token is invalid: " + err.Error())
+	}
+
+	if a.config.JWTIssuer != "" && !claims.VerifyIssuer(a.config.JWTIssuer, true) {
+		return Identity{}, errors.New("token is invalid: issuer is not accepted")
+	}
+	if a.config.JWTAudience != "" && !claims.VerifyAudience(a.config.JWTAudience, true) {
+		return Identity{}, errors.New("token is invalid: audience is not accepted")
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return Identity{}, errors.New("token is invalid: claim sub is empty")
+	}
+
+	return Identity{
+		Subject: subject,
+		Roles:   rolesOf(claims[a.config.RolesClaim]),
+		Method:  MethodJWT,
+	}, nil
+}
+
+func (a *Authenticator) keyFunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		return []byte(a.config.JWTSecret), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := a.jwks[kid]; ok {
+		return key, nil
+	}
+
+	// JWKS with one key does not need kid
+	if kid == "" && len(a.jwks) == 1 {
+		for _, key := range a.jwks {
+			return key, nil
+		}
+	}
+	return nil, errors.New("key " + kid + " not found")
+}
+
+func (a *Authenticator) checkAPIKey(key string) (Identity, error) {
+	hash := sha256.Sum256([]byte(key))
+	for _, k := range a.apiKeys {
+		if subtle.ConstantTimeCompare(hash[:], k.hash[:]) == 1 {
+			return k.identity, nil
+		}
+	}
+	return Identity{}, ErrInvalidCredentials
+}
+
+// roles claim is a list ["admin","user"] or a string "admin user"
+func rolesOf(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return strings.Fields(value)
+	case []interface{}:
+		roles := []string{}
+		for _, role := range value {
+			if r, ok := role.(string); ok {
+				roles = append(roles, r)
+			}
+		}
+		return roles
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+var secret = "testing-secret"
+
+// Help function to sign a token HS256 with secret
+func signHMAC(t *testing.T, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.Nil(t, err)
+	return token
+}
+
+func bearer(token string) metadata.MD {
+	return metadata.Pairs(AuthorizationMetadataKey, "Bearer "+token)
+}
+
+// Testing token HMAC returns subject and roles
+func TestAuthenticateWithHMAC(t *testing.T) {
+	authenticator, err := New(Config{JWTSecret: secret, RolesClaim: "roles"})
+	require.Nil(t, err)
+
+	token := signHMAC(t, jwt.MapClaims{"sub": "daniel", "roles": []string{"admin", "user"}, "exp": time.Now().Add(time.Minute).Unix()})
+	identity, found, err := authenticator.Authenticate(bearer(token))
+
+	require.Nil(t, err)
+	require.True(t, found)
+	require.Equal(t, "daniel", identity.Subject)
+	require.True(t, identity.HasRole(RoleAdmin))
+	require.Equal(t, MethodJWT, identity.Method)
+}
+
+// Testing expired token, wrong secret and token without sub are refused
+func TestAuthenticateWithTokenInvalid(t *testing.T) {
+	authenticator, _ := New(Config{JWTSecret: secret, RolesClaim: "roles"})
+
+	expired := signHMAC(t, jwt.MapClaims{"sub": "daniel", "exp": time.Now().Add(-time.Minute).Unix()})
+	_, found, err := authenticator.Authenticate(bearer(expired))
+	require.True(t, found)
+	require.NotNil(t, err)
+
+	other, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "daniel"}).SignedString([]byte("other"))
+	_, _, err = authenticator.Authenticate(bearer(other))
+	require.NotNil(t, err)
+
+	withoutSub := signHMAC(t, jwt.MapClaims{"roles": "admin"})
+	_, _, err = authenticator.Authenticate(bearer(withoutSub))
+	require.NotNil(t, err)
+	require.Equal(t, "token is invalid: claim sub is empty", err.Error())
+
+	_, _, err = authenticator.Authenticate(metadata.Pairs(AuthorizationMetadataKey, "Basic abc"))
+	require.NotNil(t, err)
+}
+
+// Testing issuer and audience are checked when configured
+func TestAuthenticateWithIssuerAndAudience(t *testing.T) {
+	authenticator, _ := New(Config{JWTSecret: secret, JWTIssuer: "kvr", JWTAudience: "api", RolesClaim: "roles"})
+
+	_, _, err := authenticator.Authenticate(bearer(signHMAC(t, jwt.MapClaims{"sub": "daniel", "iss": "other", "aud": "api"})))
+	require.NotNil(t, err)
+
+	_, _, err = authenticator.Authenticate(bearer(signHMAC(t, jwt.MapClaims{"sub": "daniel", "iss": "kvr", "aud": []string{"api"}})))
+	require.Nil(t, err)
+}
+
+// Testing token RS256 with key of JWKS file, and HMAC is refused without secret
+func TestAuthenticateWithJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	jwks, _ := json.Marshal(map[string]interface{}{"keys": []map[string]string{{
+		"kid": "key-1",
+		"kty": "RSA",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.Nil(t, os.WriteFile(path, jwks, 0600))
+
+	authenticator, err := New(Config{JWKSFile: path, RolesClaim: "roles"})
+	require.Nil(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "voter-1", "roles": "user"})
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(key)
+	require.Nil(t, err)
+
+	identity, _, err := authenticator.Authenticate(bearer(signed))
+	require.Nil(t, err)
+	require.Equal(t, "voter-1", identity.Subject)
+	require.Equal(t, []string{"user"}, identity.Roles)
+
+	// alg none or HS256 with public key must not pass
+	_, _, err = authenticator.Authenticate(bearer(signHMAC(t, jwt.MapClaims{"sub": "daniel"})))
+	require.NotNil(t, err)
+}
+
+// Testing api key of metadata x-api-key
+func TestAuthenticateWithAPIKey(t *testing.T) {
+	keys, err := parseAPIKeys("ops:admin|user:key-ops,bot:user:key:with:colon")
+	require.Nil(t, err)
+	authenticator, _ := New(Config{APIKeys: keys})
+
+	identity, found, err := authenticator.Authenticate(metadata.Pairs(APIKeyMetadataKey, "key:with:colon"))
+	require.Nil(t, err)
+	require.True(t, found)
+	require.Equal(t, "bot", identity.Subject)
+	require.Equal(t, MethodAPIKey, identity.Method)
+
+	_, _, err = authenticator.Authenticate(metadata.Pairs(APIKeyMetadataKey, "wrong"))
+	require.Equal(t, ErrInvalidCredentials, err)
+
+	_, found, err = authenticator.Authenticate(metadata.MD{})
+	require.Nil(t, err)
+	require.False(t, found)
+}
+
+// Testing requirements of default policy
+func TestDefaultPolicy(t *testing.T) {
+	policy := DefaultPolicy()
+
+	require.Equal(t, Admin, policy.For("/proto.EndPointCryptos/CreateCrypto"))
+	require.Equal(t, Authenticated, policy.For("/proto.EndPointCryptos/Upvote"))
+	require.Equal(t, Public, policy.For("/proto.EndPointCryptos/ListAllCryptos"))
+	require.Equal(t, Public, policy.For("/grpc.health.v1.Health/Check"))
+	require.Equal(t, Admin, policy.For("/proto.EndPointCryptos/NewRpc"))
+}
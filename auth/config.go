@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"api-desafio-kvr/helpers"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// APIKey is a static credential of a service, sent in metadata x-api-key
+type APIKey struct {
+	Key     string   `json:"key"`
+	Subject string   `json:"subject"`
+	Roles   []string `json:"roles"`
+}
+
+type Config struct {
+	Enabled     bool     `json:"enabled"`
+	JWTSecret   string   `json:"jwt_secret"`   // HMAC key of tokens HS256/HS384/HS512
+	JWKSFile    string   `json:"jwks_file"`    // local JWKS with RSA and EC keys of tokens RS*/ES*
+	JWTIssuer   string   `json:"jwt_issuer"`   // optional, claim iss must be equal
+	JWTAudience string   `json:"jwt_audience"` // optional, claim aud must contain it
+	RolesClaim  string   `json:"roles_claim"`  // claim with roles, list or string separated by spaces
+	APIKeys     []APIKey `json:"api_keys"`
+}
+
+// Min size of the HMAC secret and of api keys
+const (
+	MinSecretSize = 32
+	MinAPIKeySize = 16
+)
+
+// Values of examples in .env.example and config.example.json of old versions, never accepted
+var exampleSecrets = map[string]bool{
+	"change-me":       true,
+	"local-admin-key": true,
+}
+
+// Auth is disabled without config, as before it exists: the application starts and logs a warning
+func DefaultConfig() Config {
+	return Config{Enabled: false, RolesClaim: "roles"}
+}
+
+// LoadConfig reads section "auth" of CONFIG_FILE and the env vars AUTH_*.
+// AUTH_API_KEYS is a list "subject:role1|role2:key,subject:role:key".
+func LoadConfig() (Config, error) {
+	cfg := DefaultConfig()
+
+	err := helpers.LoadConfigFile("auth", &cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	envs := map[string]interface{}{
+		"AUTH_ENABLED":      &cfg.Enabled,
+		"AUTH_JWT_SECRET":   &cfg.JWTSecret,
+		"AUTH_JWKS_FILE":    &cfg.JWKSFile,
+		"AUTH_JWT_ISSUER":   &cfg.JWTIssuer,
+		"AUTH_JWT_AUDIENCE": &cfg.JWTAudience,
+		"AUTH_ROLES_CLAIM":  &cfg.RolesClaim,
+	}
+	for key, target := range envs {
+		err = helpers.SetFromEnv(key, target)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	if value := os.Getenv("AUTH_API_KEYS"); value != "" {
+		cfg.APIKeys, err = parseAPIKeys(value)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	if !cfg.Enabled {
+		return cfg, nil
+	}
+
+	if cfg.JWTSecret == "" && cfg.JWKSFile == "" && len(cfg.APIKeys) == 0 {
+		return cfg, errors.New("auth is enabled without jwt secret, jwks file or api keys")
+	}
+
+	return cfg, validateSecrets(cfg)
+}
+
+// Refuses the secrets of examples and the short ones, they can be guessed
+func validateSecrets(cfg Config) error {
+	if cfg.JWTSecret != "" {
+		if exampleSecrets[cfg.JWTSecret] || len(cfg.JWTSecret) < MinSecretSize {
+			return errors.New("auth jwt secret must be random with at least " + strconv.Itoa(MinSecretSize) + " bytes")
+		}
+	}
+
+	for _, key := range cfg.APIKeys {
+		if exampleSecrets[key.Key] || len(key.Key) < MinAPIKeySize {
+			return errors.New("auth api key of " + key.Subject + " must be random with at least " + strconv.Itoa(MinAPIKeySize) + " bytes")
+		}
+	}
+
+	return nil
+}
+
+func parseAPIKeys(value string) ([]APIKey, error) {
+	keys := []APIKey{}
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return keys, errors.New("env AUTH_API_KEYS is invalid, use subject:role1|role2:key")
+		}
+
+		keys = append(keys, APIKey{
+			Subject: parts[0],
+			Roles:   strings.Split(parts[1], "|"),
+			Key:     parts[2],
+		})
+	}
+	return keys, nil
+}
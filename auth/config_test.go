@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"api-desafio-kvr/helpers"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Testing auth is disabled without config, so the application starts as before it
+func TestLoadConfigDisabledByDefault(t *testing.T) {
+	t.Setenv(helpers.ConfigFileEnv, "")
+
+	cfg, err := LoadConfig()
+	require.Nil(t, err)
+	require.False(t, cfg.Enabled)
+}
+
+// Testing secrets of examples and short secrets are refused
+func TestLoadConfigWithWeakSecrets(t *testing.T) {
+	t.Setenv(helpers.ConfigFileEnv, "")
+	t.Setenv("AUTH_ENABLED", "true")
+
+	_, err := LoadConfig()
+	require.NotNil(t, err)
+	require.Equal(t, "auth is enabled without jwt secret, jwks file or api keys", err.Error())
+
+	t.Setenv("AUTH_JWT_SECRET", "change-me")
+	_, err = LoadConfig()
+	require.NotNil(t, err)
+	require.Equal(t, "auth jwt secret must be random with at least 32 bytes", err.Error())
+
+	t.Setenv("AUTH_JWT_SECRET", "")
+	t.Setenv("AUTH_API_KEYS", "local-admin:admin:local-admin-key")
+	_, err = LoadConfig()
+	require.NotNil(t, err)
+	require.Equal(t, "auth api key of local-admin must be random with at least 16 bytes", err.Error())
+}
+
+// Testing strong secrets are accepted
+func TestLoadConfigWithSuccess(t *testing.T) {
+	t.Setenv(helpers.ConfigFileEnv, "")
+	t.Setenv("AUTH_ENABLED", "true")
+	t.Setenv("AUTH_JWT_SECRET", "5f0c2a9e8b7d4c1f3a6e9d2b8c5f1a7e")
+	t.Setenv("AUTH_API_KEYS", "ops:admin:9d2b8c5f1a7e5f0c2a9e")
+
+	cfg, err := LoadConfig()
+	require.Nil(t, err)
+	require.True(t, cfg.Enabled)
+	require.Equal(t, []string{"admin"}, cfg.APIKeys[0].Roles)
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"api-desafio-kvr/proto"
+	"strings"
+)
+
+// Requirement of one rpc
+type Requirement int
+
+const (
+	Public        Requirement = iota // anyone, even without credentials
+	Authenticated                    // any valid token or api key
+	Admin                            // identity with role admin
+)
+
+// Policy finds the requirement by full method, then by service, then Default
+type Policy struct {
+	Methods  map[string]Requirement
+	Services map[string]Requirement
+	Default  Requirement
+}
+
+// DefaultPolicy: writes need admin, votes need an user and reads are public.
+// Rpcs not listed need admin, so a new rpc is never open by mistake.
+func DefaultPolicy() Policy {
+	service := "/" + proto.EndPointCryptos_ServiceDesc.ServiceName + "/"
+
+	return Policy{
+		Methods: map[string]Requirement{
+			service + "CreateCrypto":       Admin,
+			service + "EditCrypto":         Admin,
+			service + "DeleteCrypo":        Admin,
+			service + "RestoreCrypto":      Admin,
+			service + "PurgeDeleted":       Admin,
+			service + "Upvote":             Authenticated,
+			service + "Downvote":           Authenticated,
+			service + "RemoveVote":         Authenticated,
+			service + "FindCrypto":         Public,
+			service + "FindCryptoByAsset":  Public,
+			service + "ListAllCryptos":     Public,
+			service + "SearchCryptos":      Public,
+			service + "MonitorVotes":       Public,
+			service + "WatchCryptos":       Public,
+			service + "MonitorVotesEvents": Public,
+			service + "GetPriceHistory":    Public,
+			service + "ListAuditEvents":    Admin,
+		},
+		Services: map[string]Requirement{
+			"grpc.health.v1.Health":                    Public,
+			"grpc.reflection.v1alpha.ServerReflection": Public,
+		},
+		Default: Admin,
+	}
+}
+
+func (p Policy) For(fullMethod string) Requirement {
+	if requirement, ok := p.Methods[fullMethod]; ok {
+		return requirement
+	}
+
+	// full method is /package.Service/Method
+	service := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(service, "/"); i >= 0 {
+		service = service[:i]
+	}
+	if requirement, ok := p.Services[service]; ok {
+		return requirement
+	}
+
+	return p.Default
+}
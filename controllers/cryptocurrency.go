@@ -1,50 +1,213 @@
 package controllers
 
 import (
+	"api-desafio-kvr/auth"
 	"api-desafio-kvr/helpers"
 	"api-desafio-kvr/models"
+	"api-desafio-kvr/observer"
 	"api-desafio-kvr/proto"
 	"api-desafio-kvr/repositories"
+	"api-desafio-kvr/repositories/cache"
 	db "api-desafio-kvr/repositories/mongodb"
-	rds "api-desafio-kvr/repositories/redis"
 	"context"
 	"encoding/json"
 	"errors"
+	"os"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
 // Codes of return google.golang.org/grpc/codes
 
 var logger = &helpers.Log{}
-var observer chan string
+var hub *observer.Hub
+var backplane observer.Backplane
+
+const VoterMetadataKey = "voter-id"
 
 type AppServer struct {
 	proto.UnimplementedEndPointCryptosServer
 	Database *mongo.Collection
+	Votes    *mongo.Collection
+	Prices   *mongo.Collection
+	Audit    *mongo.Collection
+	Cache    cache.Cache
+}
+
+// Buffer size of each MonitorVotes stream is read from STREAM_BUFFER_SIZE
+// closed by StopStreams, ends all streams so the server can stop gracefully
+var streamsDone chan struct{}
+var stopStreams sync.Once
+
+func StartHub() {
+	logger.Info("", "Starting observer hub for streams")
+	bufferSize, _ := strconv.Atoi(os.Getenv("STREAM_BUFFER_SIZE"))
+	hub = observer.NewHub(bufferSize)
+	backplane = observer.NewLocal(hub, observer.DefaultLogSize)
+	streamsDone = make(chan struct{})
+	stopStreams = sync.Once{}
+}
+
+// StopStreams signals stream handlers to end, new streams end right away
+func StopStreams() {
+	stopStreams.Do(func() {
+		logger.Info("", "Stopping streams")
+		close(streamsDone)
+	})
+}
+
+// Hub of streams of this replica, the backplane feeds it
+func Hub() *observer.Hub {
+	return hub
+}
+
+// SetBackplane replaces the local backplane of StartHub
+func SetBackplane(b observer.Backplane) {
+	backplane = b
+}
+
+func StopHub() {
+	logger.Info("", "Closing observer hub for streams")
+
+	err := backplane.Close()
+	if err != nil {
+		logger.Error("", "Error to close backplane of streams: "+err.Error())
+	}
+	hub.Close()
+}
+
+// 1 while the change stream of watcher is open, then it publishes the changes and
+// the writes of this process are not published twice
+var watching int32
+
+// SetObserver publishes the crypto written by this process to the streams
+var SetObserver = func(eventType string, crypto models.CryptoCurrency) {
+	if atomic.LoadInt32(&watching) == 1 {
+		return
+	}
+
+	event := observer.Event{Type: eventType, Id: crypto.Id.Hex(), Crypto: crypto}
+	err := backplane.Publish(context.Background(), "", event)
+	if err != nil {
+		logger.Error(event.Id, "Error to publish to streams: "+err.Error())
+	}
+}
+
+// UseWatcher is the state of watcher: while its stream is open OnCryptoChange publishes the changes
+// to streams, while it is closed (error, standalone server) the writes of this process are published by SetObserver.
+// The changes of this process made while it was closed can reach the streams twice when it resumes
+func UseWatcher(open bool) {
+	var value int32
+	if open {
+		value = 1
+	}
+
+	if atomic.SwapInt32(&watching, value) != value {
+		logger.Info("", "Streams published by watcher: "+strconv.FormatBool(open))
+	}
+}
+
+// OnCryptoChange applies a change read from the change stream, of this replica or not:
+// the cache of crypto and of lists is deleted and the streams of crypto are notified
+func (a *AppServer) OnCryptoChange(ctx context.Context, change db.CryptoChange) {
+	id := change.DocumentKey.Id.Hex()
+	logger.DebugContext(ctx, id, "Crypto changed by "+change.OperationType)
+
+	err := a.Cache.Del(ctx, id)
+	if err != nil {
+		logger.ErrorContext(ctx, id, "Error to delete cache: "+err.Error())
+	}
+
+	err = a.Cache.DeleteAll(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, id, "Error to delete cache of lists: "+err.Error())
+	}
+
+	// every replica reads the change, it is published once
+	err = backplane.Publish(ctx, change.Token.Data, eventOfChange(change))
+	if err != nil {
+		logger.ErrorContext(ctx, id, "Error to publish to streams: "+err.Error())
+	}
+}
+
+// Type of event by the fields changed: deleted_at set is a delete and removed is a restore (update)
+func eventOfChange(change db.CryptoChange) observer.Event {
+	event := observer.Event{Type: observer.EventUpdated, Id: change.DocumentKey.Id.Hex()}
+	if change.FullDocument != nil {
+		event.Crypto = *change.FullDocument
+	} else {
+		event.Crypto.Id = change.DocumentKey.Id
+	}
+
+	switch change.OperationType {
+	case "insert":
+		event.Type = observer.EventCreated
+	case "delete":
+		event.Type = observer.EventDeleted
+	case "update":
+		updated := change.UpdateDescription.UpdatedFields
+		_, deleted := updated["deleted_at"]
+		_, votes := updated["votes"]
+		_, name := updated["name"]
+		_, asset := updated["asset_id"]
+		_, price := updated["price_usd"]
+
+		switch {
+		case deleted:
+			event.Type = observer.EventDeleted
+		case votes && !name && !asset && !price:
+			event.Type = observer.EventVoted
+		}
+	}
+
+	return event
 }
 
-func StartChanToStream() {
-	logger.Info("", "Starting channel for stream")
-	observer = make(chan string)
+// Saved in the transaction of the write that changed the price
+func (a *AppServer) recordPrice(ctx context.Context, crypto models.CryptoCurrency) error {
+	_, err := db.RecordPrice(ctx, a.Prices, crypto.Id, crypto.PriceUsd, crypto.UpdatedAt)
+	return err
 }
 
-var SetObserver = func(id string) {
-	observer <- id
+// Saved in the transaction of the mutation, so there is no mutation without audit
+func (a *AppServer) audit(ctx context.Context, rpcMethod string, cryptoId primitive.ObjectID, before *models.CryptoCurrency, after *models.CryptoCurrency) error {
+	event := models.AuditEvent{
+		CryptoId:  cryptoId,
+		Actor:     actorFromContext(ctx),
+		RpcMethod: rpcMethod,
+		Time:      time.Now(),
+		Changes:   models.Diff(before, after),
+	}
+
+	return db.InsertAuditEvent(ctx, a.Audit, event)
+}
+
+// Error of a transaction: the status returned by the handler as is, an error of database is Internal.
+// Errors of database are returned raw in the transaction so the transient ones are retried
+func writeError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Errorf(13, err.Error())
 }
 
 func (a *AppServer) CreateCrypto(ctx context.Context, req *proto.CreateCryptoReq) (*proto.CryptoCurrency, error) {
-	logger.Debug("", "Creating crypto received params "+req.String())
+	logger.DebugContext(ctx, "", "Creating crypto received params "+req.String())
 	cryptoResponse := proto.CryptoCurrency{}
 
 	err := helpers.ValidatorInCreateCrypto(req)
 	if err != nil {
-		logger.Error("", "Params create crypto is invalid "+req.String())
+		logger.ErrorContext(ctx, "", "Params create crypto is invalid "+req.String())
 		return &cryptoResponse, status.Errorf(3, err.Error())
 	}
 
@@ -54,156 +217,349 @@ func (a *AppServer) CreateCrypto(ctx context.Context, req *proto.CreateCryptoReq
 		PriceUsd: req.GetPriceUsd(),
 	}
 
-	insertedCrypto, err := db.InsertCryptos(a.Database, cryptoDb)
+	// The crypto, its first price and its audit are saved together
+	var insertedCrypto models.CryptoCurrency
+	err = db.WithTransaction(ctx, a.Database, func(ctx context.Context) error {
+		var err error
+		insertedCrypto, err = db.InsertCryptos(ctx, a.Database, cryptoDb)
+		if err != nil {
+			return err
+		}
+
+		err = a.recordPrice(ctx, insertedCrypto)
+		if err != nil {
+			return err
+		}
+
+		return a.audit(ctx, "CreateCrypto", insertedCrypto.Id, nil, &insertedCrypto)
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		logger.ErrorContext(ctx, "", "Crypto not created "+req.String()+" error: "+err.Error())
+		return &cryptoResponse, status.Errorf(6, "asset_id already exists: "+cryptoDb.AssetId)
+	}
 	if err != nil {
-		logger.Error("", "Crypto not created "+req.String()+" error: "+err.Error())
-		return &cryptoResponse, status.Errorf(13, err.Error())
+		logger.ErrorContext(ctx, "", "Crypto not created "+req.String()+" error: "+err.Error())
+		return &cryptoResponse, writeError(err)
 	}
 
-	// Set cache in Redis
-	err = rds.Set(insertedCrypto.Id.Hex(), insertedCrypto, rds.YesDeleteAll)
+	// Set cache
+	err = a.Cache.Set(ctx, insertedCrypto.Id.Hex(), insertedCrypto, cache.YesDeleteAll)
 	if err != nil {
-		logger.Error(insertedCrypto.Id.Hex(), "Error to set cache in redis: "+err.Error())
+		logger.ErrorContext(ctx, insertedCrypto.Id.Hex(), "Error to set cache: "+err.Error())
 	}
 
 	byteCrypto, err := json.Marshal(insertedCrypto)
 	if err != nil {
-		logger.Error(insertedCrypto.Id.Hex(), "Error in response: "+err.Error())
+		logger.ErrorContext(ctx, insertedCrypto.Id.Hex(), "Error in response: "+err.Error())
 		return &cryptoResponse, status.Errorf(13, err.Error())
 	}
 	err = json.Unmarshal(byteCrypto, &cryptoResponse)
 	if err != nil {
-		logger.Error(insertedCrypto.Id.Hex(), "Error in response: "+err.Error())
+		logger.ErrorContext(ctx, insertedCrypto.Id.Hex(), "Error in response: "+err.Error())
 		return &cryptoResponse, status.Errorf(13, err.Error())
 	}
 
 	cryptoResponse.CreatedAt = insertedCrypto.CreatedAt.Format("2006-01-02T15:04:05.999Z")
 	cryptoResponse.UpdatedAt = insertedCrypto.UpdatedAt.Format("2006-01-02T15:04:05.999Z")
 
-	logger.Info(cryptoResponse.Id, "Crypto created successful")
+	logger.InfoContext(ctx, cryptoResponse.Id, "Crypto created successful")
+
+	SetObserver(observer.EventCreated, insertedCrypto)
 	return &cryptoResponse, nil
 }
 
 func (a *AppServer) EditCrypto(ctx context.Context, req *proto.EditCryptoReq) (*proto.CryptoCurrency, error) {
-	logger.Debug("", "Editing crypto received params "+req.String())
+	logger.DebugContext(ctx, "", "Editing crypto received params "+req.String())
 	cryptoResponse := proto.CryptoCurrency{}
 
 	err := helpers.ValidatorInEditCrypto(req)
 	if err != nil {
-		logger.Error("", "Params edit crypto is invalid "+req.String())
+		logger.ErrorContext(ctx, "", "Params edit crypto is invalid "+req.String())
 		return &cryptoResponse, status.Errorf(3, err.Error())
 	}
 
 	objId, err := primitive.ObjectIDFromHex(req.GetId())
 	if err != nil {
-		logger.Error(req.GetId(), "Params edit crypto is invalid "+req.String())
+		logger.ErrorContext(ctx, req.GetId(), "Params edit crypto is invalid "+req.String())
 		return &cryptoResponse, status.Errorf(3, err.Error())
 	}
 
 	cryptoUpdate := models.CryptoCurrency{
-		Id:         objId,
-		Name:       cases.Title(language.AmericanEnglish).String(req.GetName()),
-		AssetId:    cases.Upper(language.AmericanEnglish).String(req.GetAssetId()),
-		PriceUsd:   req.GetPriceUsd(),
-		UpdateType: models.UpdateOnly,
+		Id:              objId,
+		Name:            cases.Title(language.AmericanEnglish).String(req.GetName()),
+		AssetId:         cases.Upper(language.AmericanEnglish).String(req.GetAssetId()),
+		PriceUsd:        req.GetPriceUsd(),
+		UpdateType:      models.UpdateOnly,
+		ExpectedVersion: req.ExpectedVersion,
 	}
 
-	updatedCrypto, _, err := db.UpdateCrypto(a.Database, cryptoUpdate)
-	if err != nil {
-		logger.Error("", "Crypto not edited "+req.String()+" error: "+err.Error())
-		return &cryptoResponse, status.Errorf(13, err.Error())
-	}
+	// The edit, its price and its audit are saved together, crypto is the document written by this edit
+	var crypto models.CryptoCurrency
+	err = db.WithTransaction(ctx, a.Database, func(ctx context.Context) error {
+		// Values before edit to audit
+		before, err := db.GetById(ctx, a.Database, objId)
+		if err == mongo.ErrNoDocuments {
+			return status.Errorf(5, err.Error())
+		}
+		if err != nil {
+			return err
+		}
+
+		if req.ExpectedVersion != nil && before.Version != req.GetExpectedVersion() {
+			return versionConflict(req.GetExpectedVersion(), before)
+		}
+
+		var matchedCount int64
+		crypto, matchedCount, err = db.UpdateCrypto(ctx, a.Database, cryptoUpdate)
+		if err != nil {
+			return err
+		}
 
-	crypto, err := db.GetById(a.Database, updatedCrypto.Id)
+		// other request updated (or deleted) the crypto after it was read
+		if matchedCount == 0 {
+			current, err := db.GetById(ctx, a.Database, objId)
+			if err != nil || req.ExpectedVersion == nil {
+				return status.Errorf(5, "crypto not exist")
+			}
+			return versionConflict(req.GetExpectedVersion(), current)
+		}
+
+		err = a.recordPrice(ctx, crypto)
+		if err != nil {
+			return err
+		}
+
+		return a.audit(ctx, "EditCrypto", objId, &before, &crypto)
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		logger.ErrorContext(ctx, "", "Crypto not edited "+req.String()+" error: "+err.Error())
+		return &cryptoResponse, status.Errorf(6, "asset_id already exists: "+cryptoUpdate.AssetId)
+	}
 	if err != nil {
-		logger.Error("", "Crypto not find after update "+req.String()+" error: "+err.Error())
-		return &cryptoResponse, status.Errorf(5, err.Error())
+		logger.ErrorContext(ctx, req.GetId(), "Crypto not edited "+req.String()+" error: "+err.Error())
+		return &cryptoResponse, writeError(err)
 	}
 
-	// Set cache in Redis
-	err = rds.Set(crypto.Id.Hex(), crypto, rds.YesDeleteAll)
+	// Set cache
+	err = a.Cache.Set(ctx, crypto.Id.Hex(), crypto, cache.YesDeleteAll)
 	if err != nil {
-		logger.Error(req.GetId(), "Error to set cache in redis: "+err.Error())
+		logger.ErrorContext(ctx, req.GetId(), "Error to set cache: "+err.Error())
 	}
 
 	byteCrypto, err := json.Marshal(crypto)
 	if err != nil {
-		logger.Error(crypto.Id.Hex(), "Error in response EditCrypto: "+err.Error())
+		logger.ErrorContext(ctx, crypto.Id.Hex(), "Error in response EditCrypto: "+err.Error())
 		return &cryptoResponse, status.Errorf(13, err.Error())
 	}
 	err = json.Unmarshal(byteCrypto, &cryptoResponse)
 	if err != nil {
-		logger.Error(crypto.Id.Hex(), "Error in response EditCrypto: "+err.Error())
+		logger.ErrorContext(ctx, crypto.Id.Hex(), "Error in response EditCrypto: "+err.Error())
 		return &cryptoResponse, status.Errorf(13, err.Error())
 	}
 
-	logger.Info(cryptoResponse.Id, "Crypto updated successful")
+	logger.InfoContext(ctx, cryptoResponse.Id, "Crypto updated successful")
 
-	go SetObserver(req.GetId())
+	SetObserver(observer.EventUpdated, crypto)
 	return &cryptoResponse, nil
 }
 
+// Aborted with the current crypto in details, so the client can merge and edit again with its version
+func versionConflict(expected int64, current models.CryptoCurrency) error {
+	conflict := status.New(10, "version is "+strconv.FormatInt(current.Version, 10)+
+		", expected "+strconv.FormatInt(expected, 10))
+
+	currentCrypto := current.ToProtoCrypto()
+	withCurrent, err := conflict.WithDetails(&currentCrypto)
+	if err != nil {
+		return conflict.Err()
+	}
+	return withCurrent.Err()
+}
+
 func (a *AppServer) DeleteCrypo(ctx context.Context, req *proto.DeleteCryptoReq) (*proto.DefaultResp, error) {
-	logger.Debug("", "Deleting crypto received params "+req.String())
+	logger.DebugContext(ctx, "", "Deleting crypto received params "+req.String())
 	messageResponse := proto.DefaultResp{}
 
 	err := helpers.IdValidator(req.GetId())
 	if err != nil {
-		logger.Error("", "Params to delete crypto is invalid "+req.String())
+		logger.ErrorContext(ctx, "", "Params to delete crypto is invalid "+req.String())
 		return &messageResponse, status.Errorf(3, err.Error())
 	}
 
 	objId, err := primitive.ObjectIDFromHex(req.GetId())
 	if err != nil {
-		logger.Error(req.GetId(), "Params edit crypto is invalid "+req.String())
+		logger.ErrorContext(ctx, req.GetId(), "Params edit crypto is invalid "+req.String())
 		return &messageResponse, status.Errorf(3, err.Error())
 	}
 
-	_, err = db.DeleteById(a.Database, objId)
-	if err != nil {
+	// The delete and its audit are saved together, deleted is the document written by this delete
+	var deleted models.CryptoCurrency
+	err = db.WithTransaction(ctx, a.Database, func(ctx context.Context) error {
+		// Values before delete to audit
+		before, err := db.GetById(ctx, a.Database, objId)
 		if err == mongo.ErrNoDocuments {
-			logger.Error(req.GetId(), "Delete crypto error: "+err.Error())
-			return &messageResponse, status.Errorf(5, err.Error())
+			return status.Errorf(5, err.Error())
 		}
-		logger.Error("", "Crypto not deleted "+req.String()+" error: "+err.Error())
-		return &messageResponse, status.Errorf(13, err.Error())
+		if err != nil {
+			return err
+		}
+
+		deleted, err = db.DeleteById(ctx, a.Database, objId)
+		if err == mongo.ErrNoDocuments {
+			return status.Errorf(5, err.Error())
+		}
+		if err != nil {
+			return err
+		}
+
+		return a.audit(ctx, "DeleteCrypo", objId, &before, nil)
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, req.GetId(), "Crypto not deleted "+req.String()+" error: "+err.Error())
+		return &messageResponse, writeError(err)
 	}
 
-	// Delete cache in Redis
-	err = rds.Del(req.GetId())
+	// Delete cache, lists included so they do not show the crypto deleted
+	err = a.Cache.Del(ctx, req.GetId())
+	if err != nil {
+		logger.ErrorContext(ctx, req.GetId(), "Error to delete cache: "+err.Error())
+	}
+	err = a.Cache.DeleteAll(ctx)
 	if err != nil {
-		logger.Error(req.GetId(), "Error to delete cache in redis: "+err.Error())
+		logger.ErrorContext(ctx, req.GetId(), "Error to delete cache of lists: "+err.Error())
 	}
 
 	messageResponse.Id = req.GetId()
 	messageResponse.Message = "deleted successful"
 
-	logger.Info(req.GetId(), "Crypto deleted successful")
+	logger.InfoContext(ctx, req.GetId(), "Crypto deleted successful")
 
-	go SetObserver(req.GetId())
-	return &messageResponse, err
+	SetObserver(observer.EventDeleted, deleted)
+	return &messageResponse, nil
 }
 
-func (a *AppServer) FindCrypto(ctx context.Context, req *proto.FindCryptoReq) (*proto.CryptoCurrency, error) {
-	logger.Debug("", "Finding crypto received params "+req.String())
+func (a *AppServer) RestoreCrypto(ctx context.Context, req *proto.RestoreCryptoReq) (*proto.CryptoCurrency, error) {
+	logger.DebugContext(ctx, "", "Restoring crypto received params "+req.String())
 	cryptoResponse := proto.CryptoCurrency{}
 
 	err := helpers.IdValidator(req.GetId())
 	if err != nil {
-		logger.Error("", "Params to find crypto is invalid "+req.String())
+		logger.ErrorContext(ctx, "", "Params to restore crypto is invalid "+req.String())
 		return &cryptoResponse, status.Errorf(3, err.Error())
 	}
 
-	// Get cache in Redis
-	cache := rds.Get(req.GetId())
-	if cache != nil {
-		err = json.Unmarshal([]byte(cache), &cryptoResponse)
-		if err == nil {
-			logger.Info(req.GetId(), "Found cache successful")
-			return &cryptoResponse, nil
+	objId, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return &cryptoResponse, status.Errorf(3, err.Error())
+	}
+
+	// The restore and its audit are saved together
+	var crypto models.CryptoCurrency
+	err = db.WithTransaction(ctx, a.Database, func(ctx context.Context) error {
+		// The crypto deleted is the before of audit
+		before, err := db.GetDeletedById(ctx, a.Database, objId)
+		if err == mongo.ErrNoDocuments {
+			return status.Errorf(5, "crypto deleted not found: "+req.GetId())
+		}
+		if err != nil {
+			return err
+		}
+
+		crypto, err = db.RestoreById(ctx, a.Database, objId)
+		if err == mongo.ErrNoDocuments {
+			return status.Errorf(5, "crypto deleted not found: "+req.GetId())
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return status.Errorf(6, "asset_id of crypto is used by other crypto: "+req.GetId())
+		}
+		if err != nil {
+			return err
+		}
+
+		return a.audit(ctx, "RestoreCrypto", objId, &before, &crypto)
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, req.GetId(), "Crypto not restored "+req.String()+" error: "+err.Error())
+		return &cryptoResponse, writeError(err)
+	}
+
+	// Set cache
+	err = a.Cache.Set(ctx, crypto.Id.Hex(), crypto, cache.YesDeleteAll)
+	if err != nil {
+		logger.ErrorContext(ctx, req.GetId(), "Error to set cache: "+err.Error())
+	}
+
+	cryptoResponse = crypto.ToProtoCrypto()
+
+	logger.InfoContext(ctx, req.GetId(), "Crypto restored successful")
+
+	SetObserver(observer.EventUpdated, crypto)
+	return &cryptoResponse, nil
+}
+
+// Removes for good the cryptos deleted before the retention, with their votes and price history
+func (a *AppServer) PurgeDeleted(ctx context.Context, req *proto.PurgeDeletedReq) (*proto.PurgeDeletedResp, error) {
+	logger.DebugContext(ctx, "", "Purging deleted cryptos received params "+req.String())
+	purgeResponse := proto.PurgeDeletedResp{}
+
+	err := helpers.RetentionValidator(req.GetRetentionDays())
+	if err != nil {
+		logger.ErrorContext(ctx, "", "Params to purge deleted cryptos is invalid "+req.String())
+		return &purgeResponse, status.Errorf(3, err.Error())
+	}
+
+	days := int(req.GetRetentionDays())
+	if days == 0 {
+		days = helpers.DefaultRetentionDays
+	}
+
+	cryptos, err := db.ListDeletedBefore(ctx, a.Database, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		logger.ErrorContext(ctx, "", "Error to find deleted cryptos: "+err.Error())
+		return &purgeResponse, status.Errorf(13, err.Error())
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(cryptos))
+	for _, crypto := range cryptos {
+		ids = append(ids, crypto.Id)
+	}
+
+	// The purge and its audits are saved together, no crypto is removed without trace
+	var purged int64
+	err = db.WithTransaction(ctx, a.Database, func(ctx context.Context) error {
+		var err error
+		purged, err = db.PurgeByIds(ctx, a.Database, []db.IMCollection{a.Votes, a.Prices}, ids)
+		if err != nil {
+			return err
+		}
+
+		for i := range cryptos {
+			err = a.audit(ctx, "PurgeDeleted", cryptos[i].Id, &cryptos[i], nil)
+			if err != nil {
+				return err
+			}
 		}
-		// else continue
-		logger.Warn(req.GetId(), err.Error())
+		return nil
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "", "Error to purge deleted cryptos: "+err.Error())
+		return &purgeResponse, writeError(err)
+	}
+
+	purgeResponse.Purged = purged
+
+	logger.InfoContext(ctx, "", "Purged "+strconv.FormatInt(purged, 10)+" cryptos deleted before "+strconv.Itoa(days)+" days")
+	return &purgeResponse, nil
+}
+
+func (a *AppServer) FindCrypto(ctx context.Context, req *proto.FindCryptoReq) (*proto.CryptoCurrency, error) {
+	logger.DebugContext(ctx, "", "Finding crypto received params "+req.String())
+	cryptoResponse := proto.CryptoCurrency{}
+
+	err := helpers.IdValidator(req.GetId())
+	if err != nil {
+		logger.ErrorContext(ctx, "", "Params to find crypto is invalid "+req.String())
+		return &cryptoResponse, status.Errorf(3, err.Error())
 	}
 
 	objId, err := primitive.ObjectIDFromHex(req.GetId())
@@ -211,58 +567,73 @@ func (a *AppServer) FindCrypto(ctx context.Context, req *proto.FindCryptoReq) (*
 		return &cryptoResponse, nil
 	}
 
-	findResp, err := db.GetById(a.Database, objId)
+	// Get cache, in a miss only one request of the id finds in database
+	byteFind, err := a.Cache.Remember(ctx, req.GetId(), func(ctx context.Context) ([]byte, error) {
+		findResp, err := db.GetById(ctx, a.Database, objId)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(findResp)
+	})
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			logger.Error(req.GetId(), "Find crypto error: "+err.Error())
+			logger.ErrorContext(ctx, req.GetId(), "Find crypto error: "+err.Error())
 			return &cryptoResponse, status.Errorf(5, err.Error())
 		}
-		logger.Error("", "Crypto not found because error "+req.String()+" error: "+err.Error())
+		logger.ErrorContext(ctx, "", "Crypto not found because error "+req.String()+" error: "+err.Error())
 		return &cryptoResponse, status.Errorf(13, err.Error())
 	}
 
-	// Set cache in Redis
-	err = rds.Set(findResp.Id.Hex(), findResp, rds.YesDeleteAll)
+	err = json.Unmarshal(byteFind, &cryptoResponse)
 	if err != nil {
-		logger.Error(findResp.Id.Hex(), "Error to set cache in redis: "+err.Error())
+		logger.ErrorContext(ctx, req.GetId(), "Error in response FindCrypto: "+err.Error())
+		return &cryptoResponse, status.Errorf(13, err.Error())
 	}
 
-	byteFind, err := json.Marshal(findResp)
+	logger.InfoContext(ctx, req.GetId(), "Crypto found successful")
+	return &cryptoResponse, nil
+}
+
+func (a *AppServer) FindCryptoByAsset(ctx context.Context, req *proto.FindCryptoByAssetReq) (*proto.CryptoCurrency, error) {
+	logger.DebugContext(ctx, "", "Finding crypto by asset received params "+req.String())
+	cryptoResponse := proto.CryptoCurrency{}
+
+	err := helpers.AssetValidator(req.GetAssetId())
 	if err != nil {
-		logger.Error(findResp.Id.Hex(), "Error in response FindCrypto: "+err.Error())
-		return &cryptoResponse, status.Errorf(13, err.Error())
+		logger.ErrorContext(ctx, "", "Params to find crypto by asset is invalid "+req.String())
+		return &cryptoResponse, status.Errorf(3, err.Error())
 	}
-	err = json.Unmarshal(byteFind, &cryptoResponse)
+
+	findResp, err := db.GetByAsset(ctx, a.Database, req.GetAssetId())
 	if err != nil {
-		logger.Error(findResp.Id.Hex(), "Error in response FindCrypto: "+err.Error())
+		if err == mongo.ErrNoDocuments {
+			logger.ErrorContext(ctx, req.GetAssetId(), "Find crypto by asset error: "+err.Error())
+			return &cryptoResponse, status.Errorf(5, err.Error())
+		}
+		logger.ErrorContext(ctx, "", "Crypto not found because error "+req.String()+" error: "+err.Error())
 		return &cryptoResponse, status.Errorf(13, err.Error())
 	}
 
-	logger.Info(req.GetId(), "Crypto found successful")
+	cryptoResponse = findResp.ToProtoCrypto()
+
+	logger.InfoContext(ctx, cryptoResponse.Id, "Crypto found by asset "+req.GetAssetId()+" successful")
 	return &cryptoResponse, nil
 }
 
 func (a *AppServer) ListAllCryptos(ctx context.Context, req *proto.SortCryptosReq) (*proto.ListCryptosResp, error) {
-	logger.Debug("", "Listing crypto received params "+req.String())
+	logger.DebugContext(ctx, "", "Listing crypto received params "+req.String())
 	cryptoListResponse := proto.ListCryptosResp{}
 
 	err := helpers.ValidatorListAllCryptos(req)
 	if err != nil {
-		logger.Error("", "Params to list crypto is invalid "+req.String())
+		logger.ErrorContext(ctx, "", "Params to list crypto is invalid "+req.String())
 		return &cryptoListResponse, status.Errorf(3, err.Error())
 	}
 
-	// Get cache in Redis
-	key := rds.PrefixDeleteAll + "-" + req.GetFieldSort() + "-" + strconv.FormatBool(req.GetOrderBy())
-	cryptos := rds.Get(key)
-	if cryptos != nil {
-		err = json.Unmarshal([]byte(cryptos), &cryptoListResponse)
-		if err == nil {
-			logger.Info(key, "Found cache successful")
-			return &cryptoListResponse, nil
-		}
-		// else continue
-		logger.Warn(key, err.Error())
+	// without page_size and page_token all cryptos are listed, as before the pagination
+	pageSize := req.GetPageSize()
+	if pageSize == 0 && req.GetPageToken() != "" {
+		pageSize = helpers.DefaultPageSize
 	}
 
 	// if GetOrderBy == true then orderBy is ASC, else orderBy is DESC
@@ -271,198 +642,632 @@ func (a *AppServer) ListAllCryptos(ctx context.Context, req *proto.SortCryptosRe
 		Asc:   req.GetOrderBy(),
 	}
 
-	response, err := db.ListAll(a.Database, sort)
+	page := repositories.PageParams{
+		Size:  int64(pageSize),
+		Token: req.GetPageToken(),
+	}
+
+	// Get cache, each page has its own key
+	key := cache.PrefixDeleteAll + "-" + req.GetFieldSort() + "-" + strconv.FormatBool(req.GetOrderBy()) +
+		"-" + strconv.Itoa(int(pageSize)) + "-" + req.GetPageToken()
+	byteList, err := a.Cache.Remember(ctx, key, func(ctx context.Context) ([]byte, error) {
+		return a.listPage(ctx, sort, page)
+	})
+	if err != nil {
+		if err == db.ErrInvalidPageToken {
+			logger.ErrorContext(ctx, "", "Params to list crypto is invalid "+req.String())
+			return &cryptoListResponse, status.Errorf(3, err.Error())
+		}
+		logger.ErrorContext(ctx, "", "Cryptos not listed because error "+req.String()+" error: "+err.Error())
+		return &cryptoListResponse, status.Errorf(13, err.Error())
+	}
+
+	err = json.Unmarshal(byteList, &cryptoListResponse)
 	if err != nil {
-		logger.Error("", "Cryptos not listed because error "+req.String()+" error: "+err.Error())
+		logger.ErrorContext(ctx, "", "Error in response ListAllCryptos: "+err.Error())
 		return &cryptoListResponse, status.Errorf(13, err.Error())
 	}
 
+	// json of empty list has not the field crypto
+	if cryptoListResponse.Crypto == nil {
+		cryptoListResponse.Crypto = []*proto.CryptoCurrency{}
+	}
+
+	amount := len(cryptoListResponse.Crypto)
+	logger.InfoContext(ctx, "", "Listed "+strconv.Itoa(amount)+" crypto successful")
+	return &cryptoListResponse, nil
+}
+
+// Finds the page in database and returns the response in json to cache
+func (a *AppServer) listPage(ctx context.Context, sort repositories.SortParams, page repositories.PageParams) ([]byte, error) {
+	response, nextToken, err := db.ListPage(ctx, a.Database, sort, page)
+	if err != nil {
+		return nil, err
+	}
+
+	totalCount, err := db.CountDocuments(ctx, a.Database)
+	if err != nil {
+		return nil, err
+	}
+
 	cryptoList := []*proto.CryptoCurrency{}
 	for _, value := range response {
 		proto := value.ToProtoCrypto()
 		cryptoList = append(cryptoList, &proto)
 	}
 
-	amount := len(cryptoList)
-	cryptoListResponse.Crypto = cryptoList
+	cryptoListResponse := proto.ListCryptosResp{
+		Crypto:        cryptoList,
+		NextPageToken: nextToken,
+		TotalCount:    totalCount,
+	}
 
-	byteCrypto, err := json.Marshal(cryptoListResponse)
+	return json.Marshal(&cryptoListResponse)
+}
+
+func (a *AppServer) SearchCryptos(ctx context.Context, req *proto.SearchCryptosReq) (*proto.ListCryptosResp, error) {
+	logger.DebugContext(ctx, "", "Searching crypto received params "+req.String())
+	cryptoListResponse := proto.ListCryptosResp{}
+
+	err := helpers.ValidatorSearchCryptos(req)
 	if err != nil {
-		logger.Error("", "Error to set cache in redis: "+err.Error())
-		return &cryptoListResponse, nil
+		logger.ErrorContext(ctx, "", "Params to search crypto is invalid "+req.String())
+		return &cryptoListResponse, status.Errorf(3, err.Error())
+	}
+
+	search := repositories.SearchParams{
+		Name:       req.GetName(),
+		NamePrefix: req.GetNamePrefix(),
+		AssetId:    req.GetAssetId(),
+		MinPrice:   req.MinPriceUsd,
+		MaxPrice:   req.MaxPriceUsd,
+		MinVotes:   req.MinVotes,
+		MaxVotes:   req.MaxVotes,
 	}
-	// Set cache in Redis
-	key = rds.PrefixDeleteAll + "-" + sort.Field + "-" + strconv.FormatBool(sort.Asc)
-	err = rds.SetByByte(key, string(byteCrypto), rds.NoDeleteAll)
+
+	// if GetOrderBy == true then orderBy is ASC, else orderBy is DESC
+	sort := repositories.SortParams{
+		Field: req.GetFieldSort(),
+		Asc:   req.GetOrderBy(),
+	}
+
+	// unlike ListAllCryptos the search is always paginated, a short name matches most cryptos
+	pageSize := req.GetPageSize()
+	if pageSize == 0 {
+		pageSize = helpers.DefaultPageSize
+	}
+
+	page := repositories.PageParams{
+		Size:  int64(pageSize),
+		Token: req.GetPageToken(),
+	}
+
+	response, nextToken, err := db.Search(ctx, a.Database, search, sort, page)
 	if err != nil {
-		logger.Error("", "Error to set cache in redis: "+err.Error())
+		if err == db.ErrInvalidPageToken {
+			logger.ErrorContext(ctx, "", "Params to search crypto is invalid "+req.String())
+			return &cryptoListResponse, status.Errorf(3, err.Error())
+		}
+		logger.ErrorContext(ctx, "", "Cryptos not searched because error "+req.String()+" error: "+err.Error())
+		return &cryptoListResponse, status.Errorf(13, err.Error())
+	}
+
+	totalCount, err := db.CountSearch(ctx, a.Database, search)
+	if err != nil {
+		logger.ErrorContext(ctx, "", "Cryptos searched not counted because error "+req.String()+" error: "+err.Error())
+		return &cryptoListResponse, status.Errorf(13, err.Error())
 	}
 
-	logger.Info("", "Listed "+strconv.Itoa(amount)+" crypto successful")
+	cryptoList := []*proto.CryptoCurrency{}
+	for _, value := range response {
+		proto := value.ToProtoCrypto()
+		cryptoList = append(cryptoList, &proto)
+	}
+
+	cryptoListResponse.Crypto = cryptoList
+	cryptoListResponse.NextPageToken = nextToken
+	cryptoListResponse.TotalCount = totalCount
+
+	logger.InfoContext(ctx, "", "Searched "+strconv.Itoa(len(cryptoList))+" crypto successful")
 	return &cryptoListResponse, nil
 }
 
 func (a *AppServer) Upvote(ctx context.Context, req *proto.VoteReq) (*proto.DefaultResp, error) {
-	logger.Debug(req.GetId(), "Upvoting crypto received params "+req.String())
+	logger.DebugContext(ctx, req.GetId(), "Upvoting crypto received params "+req.String())
+	return a.registerVote(ctx, req, models.VoteUp, "Upvote", "upvote")
+}
+
+func (a *AppServer) Downvote(ctx context.Context, req *proto.VoteReq) (*proto.DefaultResp, error) {
+	logger.DebugContext(ctx, req.GetId(), "Downvoting crypto received params "+req.String())
+	return a.registerVote(ctx, req, models.VoteDown, "Downvote", "downvote")
+}
+
+func (a *AppServer) RemoveVote(ctx context.Context, req *proto.VoteReq) (*proto.DefaultResp, error) {
+	logger.DebugContext(ctx, req.GetId(), "Removing vote of crypto received params "+req.String())
+	return a.registerVote(ctx, req, models.VoteNone, "RemoveVote", "remove vote")
+}
+
+// Saves the vote of caller and moves the votes of crypto by the difference to previous vote,
+// so voting again is idempotent and switching up to down moves 2
+func (a *AppServer) registerVote(ctx context.Context, req *proto.VoteReq, value int32, rpcMethod string, action string) (*proto.DefaultResp, error) {
 	responseMessage := proto.DefaultResp{}
 
 	err := helpers.IdValidator(req.GetId())
 	if err != nil {
-		logger.Error(req.GetId(), "Params to upvote crypto is invalid "+req.String())
+		logger.ErrorContext(ctx, req.GetId(), "Params to "+action+" crypto is invalid "+req.String())
 		return &responseMessage, status.Errorf(3, err.Error())
 	}
 
+	voterId, err := voterFromContext(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, req.GetId(), "Voter of "+action+" is invalid: "+err.Error())
+		return &responseMessage, status.Errorf(16, err.Error())
+	}
+
 	objId, err := primitive.ObjectIDFromHex(req.GetId())
 	if err != nil {
 		return &responseMessage, status.Errorf(3, err.Error())
 	}
 
-	crypto := models.CryptoCurrency{
-		Id:         objId,
-		UpdateType: models.UpVote,
-	}
+	// The vote of voter, the votes of crypto and the audit are saved together
+	var previous int32
+	var crypto models.CryptoCurrency
+	err = db.WithTransaction(ctx, a.Database, func(ctx context.Context) error {
+		// Verifying if crypto exists before save the vote
+		before, err := db.GetById(ctx, a.Database, objId)
+		if err == mongo.ErrNoDocuments {
+			return status.Errorf(5, err.Error())
+		}
+		if err != nil {
+			return err
+		}
 
-	_, matchedCount, err := db.UpdateCrypto(a.Database, crypto)
-	if err != nil {
-		logger.Error(req.GetId(), "Crypto upvote error: "+err.Error())
-		return &responseMessage, status.Errorf(13, err.Error())
-	}
+		previous, err = db.SetVote(ctx, a.Votes, voterId, objId, value)
+		if err != nil {
+			return err
+		}
+
+		// vote did not change
+		if value == previous {
+			return nil
+		}
+
+		update := models.CryptoCurrency{
+			Id:         objId,
+			UpdateType: models.UpdateVotes,
+			VotesDelta: value - previous,
+		}
+
+		var matchedCount int64
+		crypto, matchedCount, err = db.UpdateCrypto(ctx, a.Database, update)
+		if err != nil || matchedCount == 0 {
+			// Out of transaction undo vote to keep votes of crypto equal to votes of voters
+			if !db.InTransaction(ctx) {
+				_, errUndo := db.SetVote(ctx, a.Votes, voterId, objId, previous)
+				if errUndo != nil {
+					logger.ErrorContext(ctx, req.GetId(), "Error to undo vote of "+voterId+": "+errUndo.Error())
+				}
+			}
 
-	crypto, errGet := db.GetById(a.Database, crypto.Id)
+			if err != nil {
+				return err
+			}
 
-	// if document not updated, verify if exist
-	if matchedCount == 0 {
-		// Verifying reason to mathedCount == 0 maybe crypto not exists
-		if errGet != nil {
-			logger.Error(req.GetId(), "Upvote error: "+errGet.Error())
-			return &responseMessage, status.Errorf(5, errGet.Error())
+			// crypto was deleted after it was found
+			return status.Errorf(5, "crypto not exist")
 		}
 
-		err = errors.New("crypto not exist")
-		logger.Error(req.GetId(), err.Error())
-		return &responseMessage, status.Errorf(13, err.Error())
+		return a.audit(ctx, rpcMethod, objId, &before, &crypto)
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, req.GetId(), "Crypto "+action+" error: "+err.Error())
+		return &responseMessage, writeError(err)
 	}
 
 	responseMessage.Id = req.GetId()
-	responseMessage.Message = "registered upvote successful"
 
-	logger.Info(req.GetId(), "Crypto upvote successful")
+	if value == previous {
+		responseMessage.Message = action + " already registered"
+		logger.InfoContext(ctx, req.GetId(), "Crypto "+action+" already registered by "+voterId)
+		return &responseMessage, nil
+	}
+
+	responseMessage.Message = "registered " + action + " successful"
+	logger.InfoContext(ctx, req.GetId(), "Crypto "+action+" successful")
 
-	// Set cache in Redis
-	err = rds.Set(crypto.Id.Hex(), crypto, rds.YesDeleteAll)
+	// Set cache with the crypto written by this vote
+	err = a.Cache.Set(ctx, crypto.Id.Hex(), crypto, cache.YesDeleteAll)
 	if err != nil {
-		logger.Error(req.GetId(), "Error to set cache in redis: "+err.Error())
+		logger.ErrorContext(ctx, req.GetId(), "Error to set cache: "+err.Error())
 	}
 
-	go SetObserver(req.GetId())
+	SetObserver(observer.EventVoted, crypto)
 	return &responseMessage, nil
 }
 
-func (a *AppServer) Downvote(ctx context.Context, req *proto.VoteReq) (*proto.DefaultResp, error) {
-	logger.Debug("", "Downvoting crypto received params "+req.String())
-	responseMessage := proto.DefaultResp{}
-	responseMessage.Id = req.GetId()
+// MonitorVotes sends the crypto on each change of one id, the resume by since_sequence is in MonitorVotesEvents
+func (a *AppServer) MonitorVotes(req *proto.MonitorVotesReq, stream proto.EndPointCryptos_MonitorVotesServer) error {
+	if req.SinceSequence != nil {
+		logger.ErrorContext(stream.Context(), "", "Params to stream crypto is invalid "+req.String())
+		return status.Errorf(3, "since_sequence is accepted only by MonitorVotesEvents")
+	}
+
+	return a.monitorVotes(req, cryptoStream{stream})
+}
+
+// MonitorVotesEvents is MonitorVotes with the sequence and type of each event, since_sequence replays the ones after it
+func (a *AppServer) MonitorVotesEvents(req *proto.MonitorVotesReq, stream proto.EndPointCryptos_MonitorVotesEventsServer) error {
+	return a.monitorVotes(req, stream)
+}
+
+// Stream of MonitorVotes sends only the crypto of events, it has no replay so no GAP
+type cryptoStream struct {
+	proto.EndPointCryptos_MonitorVotesServer
+}
+
+func (s cryptoStream) Send(event *proto.CryptoEvent) error {
+	return s.EndPointCryptos_MonitorVotesServer.Send(event.Crypto)
+}
 
+func (a *AppServer) monitorVotes(req *proto.MonitorVotesReq, stream eventStream) error {
 	err := helpers.IdValidator(req.GetId())
+	if err == nil {
+		err = helpers.SinceSequenceValidator(req.SinceSequence)
+	}
 	if err != nil {
-		logger.Error(req.GetId(), "Params to downvote crypto is invalid")
-		return &responseMessage, status.Errorf(3, err.Error())
+		logger.ErrorContext(stream.Context(), "", "Params to stream crypto is invalid "+req.String())
+		return status.Errorf(3, err.Error())
 	}
 
-	objId, err := primitive.ObjectIDFromHex(req.GetId())
-	if err != nil {
-		return &responseMessage, status.Errorf(3, err.Error())
+	logger.InfoContext(stream.Context(), req.GetId(), "Streaming crypto...")
+	subscription := subscribe(req.SinceSequence, req.GetId())
+	defer subscription.Unsubscribe()
+
+	// replay and live send the crypto of event, as it was after that change
+	send := func(event observer.Event) error {
+		// deleted crypto is hidden, the stream sends it again if it is restored
+		if event.Type == observer.EventDeleted {
+			logger.InfoContext(stream.Context(), req.GetId(), "Crypto of stream is deleted")
+			return nil
+		}
+
+		streamEvent := toProtoEvent(event)
+		err := stream.Send(streamEvent)
+		if err != nil {
+			return err
+		}
+
+		out, err := json.Marshal(streamEvent)
+		if err != nil {
+			logger.ErrorContext(stream.Context(), req.GetId(), err.Error())
+		}
+		logger.InfoContext(stream.Context(), req.GetId(), "Streaming in Crypto "+string(out))
+		return nil
 	}
 
-	crypto := models.CryptoCurrency{
-		Id:         objId,
-		UpdateType: models.DownVote,
+	// live events up to it were sent by the replay
+	var lastSent int64
+	if req.SinceSequence != nil {
+		lastSent, err = replay(stream, req.GetSinceSequence(), []string{req.GetId()}, subscription, send)
+		if err != nil {
+			return streamClosed(stream.Context(), req.GetId(), err)
+		}
 	}
 
-	_, matchedCount, err := db.UpdateCrypto(a.Database, crypto)
+	for {
+		var event observer.Event
+		var ok bool
+
+		select {
+		case <-stream.Context().Done():
+			logger.InfoContext(stream.Context(), req.GetId(), "Stream finished by client")
+			return nil
+		case <-streamsDone:
+			logger.InfoContext(stream.Context(), req.GetId(), "Stream finished by shutdown")
+			return status.Errorf(14, "server is shutting down")
+		case event, ok = <-subscription.Events():
+		}
+
+		// queue closed by hub
+		if !ok {
+			if subscription.Err() == observer.ErrSlowConsumer {
+				logger.WarnContext(stream.Context(), req.GetId(), "Stream disconnected: "+subscription.Err().Error())
+				return status.Errorf(8, subscription.Err().Error())
+			}
+			logger.InfoContext(stream.Context(), req.GetId(), "Stream closed by server")
+			return nil
+		}
+
+		if event.Sequence <= lastSent {
+			continue
+		}
+
+		err = send(event)
+		if err != nil {
+			return streamClosed(stream.Context(), req.GetId(), err)
+		}
+	}
+}
+
+// Error of send, nil when the client is gone
+func streamClosed(ctx context.Context, id string, err error) error {
+	errStatus := status.Convert(err)
+	if cases.Lower(language.AmericanEnglish).String(errStatus.Message()) == "transport is closing" {
+		logger.WarnContext(ctx, id, "Stream "+errStatus.Message())
+		return nil
+	}
+	return err
+}
+
+// WatchCryptos sends the events of a list of cryptos or of all (new ones included), with the crypto after the change
+func (a *AppServer) WatchCryptos(req *proto.WatchCryptosReq, stream proto.EndPointCryptos_WatchCryptosServer) error {
+	err := helpers.ValidatorWatchCryptos(req)
 	if err != nil {
-		logger.Error(req.GetId(), "Crypto downvote error: "+err.Error())
-		return &responseMessage, status.Errorf(13, err.Error())
+		logger.ErrorContext(stream.Context(), "", "Params to watch cryptos is invalid "+req.String())
+		return status.Errorf(3, err.Error())
 	}
 
-	crypto, errGet := db.GetById(a.Database, crypto.Id)
+	ids := req.GetIds()
+	if req.GetAll() {
+		ids = []string{observer.All}
+	}
 
-	if matchedCount == 0 {
-		// Verifying reason to mathedCount == 0 maybe crypto not exists
-		if errGet != nil {
-			logger.Error(req.GetId(), "Downvote error: "+errGet.Error())
-			return &responseMessage, status.Errorf(5, errGet.Error())
+	logger.InfoContext(stream.Context(), "", "Watching cryptos "+strings.Join(ids, ","))
+	subscription := subscribe(req.SinceSequence, ids...)
+	defer subscription.Unsubscribe()
+
+	send := func(event observer.Event) error {
+		return stream.Send(toProtoEvent(event))
+	}
+
+	// live events up to it were sent by the replay
+	var lastSent int64
+	if req.SinceSequence != nil {
+		lastSent, err = replay(stream, req.GetSinceSequence(), ids, subscription, send)
+		if err != nil {
+			return err
 		}
-		// vote is 0
-		err = errors.New("unchanged crypto because vote = 0")
-		logger.Error(req.GetId(), err.Error())
-		return &responseMessage, status.Errorf(13, err.Error())
 	}
 
-	responseMessage.Message = "registered downvote successful"
-	logger.Info(req.GetId(), "Crypto downvote successful")
+	for {
+		var event observer.Event
+		var ok bool
+
+		select {
+		case <-stream.Context().Done():
+			logger.InfoContext(stream.Context(), "", "Watch finished by client")
+			return nil
+		case <-streamsDone:
+			logger.InfoContext(stream.Context(), "", "Watch finished by shutdown")
+			return status.Errorf(14, "server is shutting down")
+		case event, ok = <-subscription.Events():
+		}
 
-	// Set cache in Redis
-	err = rds.Set(crypto.Id.Hex(), crypto, rds.YesDeleteAll)
-	if err != nil {
-		logger.Error(req.GetId(), "Error to set cache in redis: "+err.Error())
+		// queue closed by hub
+		if !ok {
+			if subscription.Err() == observer.ErrSlowConsumer {
+				logger.WarnContext(stream.Context(), "", "Watch disconnected: "+subscription.Err().Error())
+				return status.Errorf(8, subscription.Err().Error())
+			}
+			logger.InfoContext(stream.Context(), "", "Watch closed by server")
+			return nil
+		}
+
+		if event.Sequence <= lastSent {
+			continue
+		}
+
+		err = send(event)
+		if err != nil {
+			logger.WarnContext(stream.Context(), event.Id, "Error to send event of watch: "+err.Error())
+			return err
+		}
 	}
+}
 
-	go SetObserver(req.GetId())
-	return &responseMessage, nil
+// Stream of CryptoEvent, MonitorVotesEvents and WatchCryptos
+type eventStream interface {
+	Send(*proto.CryptoEvent) error
+	Context() context.Context
 }
 
-func (a *AppServer) MonitorVotes(req *proto.MonitorVotesReq, stream proto.EndPointCryptos_MonitorVotesServer) error {
-	err := helpers.IdValidator(req.GetId())
+// With since the subscription is held, so the live events are kept while the replay is sent
+func subscribe(since *int64, ids ...string) *observer.Subscription {
+	if since != nil {
+		return hub.SubscribeHeld(ids...)
+	}
+	return hub.Subscribe(ids...)
+}
+
+// Events of log after since of the ids watched, to send before the live ones. gap is true when some were evicted
+func replayEvents(ctx context.Context, since int64, ids []string) ([]observer.Event, bool, error) {
+	events, gap, err := backplane.Since(ctx, since)
 	if err != nil {
-		logger.Error("", "Params to stream crypto is invalid "+req.String())
-		return status.Errorf(3, err.Error())
+		return nil, false, err
 	}
 
-	logger.Info(req.GetId(), "Streaming crypto...")
+	watched := map[string]bool{}
+	for _, id := range ids {
+		watched[id] = true
+	}
 
-	for {
-		// received id to observer chan
-		cryptoUpdatedId := <-observer
+	replay := []observer.Event{}
+	for _, event := range events {
+		if watched[observer.All] || watched[event.Id] {
+			replay = append(replay, event)
+		}
+	}
 
-		if cryptoUpdatedId == req.GetId() {
+	return replay, gap, nil
+}
 
-			objId, err := primitive.ObjectIDFromHex(cryptoUpdatedId)
-			if err != nil {
-				return err
-			}
+// Sends GAP when events were evicted, the events of log after since and the ones held in subscription
+// meanwhile, then the subscription goes to its queue. Returns the last sequence replayed
+func replay(stream eventStream, since int64, ids []string, subscription *observer.Subscription, send func(observer.Event) error) (int64, error) {
+	last := since
 
-			cryptoFound, err := db.GetById(a.Database, objId)
-			if err != nil {
-				logger.Error(req.GetId(), "Error to stram crypto: "+err.Error())
-				return err
-			}
+	events, gap, err := replayEvents(stream.Context(), since, ids)
+	if err != nil {
+		logger.ErrorContext(stream.Context(), "", "Error to replay events: "+err.Error())
+		return last, status.Errorf(13, err.Error())
+	}
 
-			streamCrypto := &proto.CryptoCurrency{
-				Id:        cryptoFound.Id.Hex(),
-				Name:      cryptoFound.Name,
-				AssetId:   cryptoFound.AssetId,
-				PriceUsd:  cryptoFound.PriceUsd,
-				Votes:     cryptoFound.Votes,
-				CreatedAt: cryptoFound.CreatedAt.Format("2006-01-02T15:04:05.999Z"),
-				UpdatedAt: cryptoFound.UpdatedAt.Format("2006-01-02T15:04:05.999Z"),
-			}
-			err = stream.Send(streamCrypto)
+	if gap {
+		logger.WarnContext(stream.Context(), "", "Events after sequence "+strconv.FormatInt(since, 10)+" were evicted")
+		err = stream.Send(&proto.CryptoEvent{Sequence: since, Type: proto.CryptoEventType_GAP})
+		if err != nil {
+			return last, err
+		}
+	}
 
-			if err != nil {
-				errStatus := status.Convert(err)
-				if cases.Lower(language.AmericanEnglish).String(errStatus.Message()) == "transport is closing" {
-					logger.Warn(req.GetId(), "Stream "+errStatus.Message())
-					return nil
-				}
-				return err
+	replayed := 0
+	for {
+		for _, event := range events {
+			// held events may be in log too
+			if event.Sequence <= last {
+				continue
 			}
 
-			out, err := json.Marshal(streamCrypto)
+			err = send(event)
 			if err != nil {
-				logger.Error(req.GetId(), err.Error())
+				return last, err
 			}
-			logger.Info(req.GetId(), "Streaming in Crypto "+string(out))
+			last = event.Sequence
+			replayed++
+		}
+
+		// none held ends the hold
+		events = subscription.Release()
+		if len(events) == 0 {
+			break
+		}
+	}
+
+	logger.InfoContext(stream.Context(), "", "Replayed "+strconv.Itoa(replayed)+" events")
+	return last, nil
+}
+
+func toProtoEvent(event observer.Event) *proto.CryptoEvent {
+	crypto := event.Crypto.ToProtoCrypto()
+	return &proto.CryptoEvent{
+		Sequence: event.Sequence,
+		Type:     proto.CryptoEventType(proto.CryptoEventType_value[event.Type]),
+		Crypto:   &crypto,
+	}
+}
+
+func (a *AppServer) GetPriceHistory(ctx context.Context, req *proto.PriceHistoryReq) (*proto.PriceHistoryResp, error) {
+	logger.DebugContext(ctx, "", "Getting price history received params "+req.String())
+	historyResponse := proto.PriceHistoryResp{}
+
+	from, to, interval, err := helpers.ValidatorPriceHistory(req, time.Now())
+	if err != nil {
+		logger.ErrorContext(ctx, "", "Params to price history is invalid "+req.String())
+		return &historyResponse, status.Errorf(3, err.Error())
+	}
+
+	objId, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return &historyResponse, status.Errorf(3, err.Error())
+	}
+
+	_, err = db.GetById(ctx, a.Database, objId)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			logger.ErrorContext(ctx, req.GetId(), "Price history error: "+err.Error())
+			return &historyResponse, status.Errorf(5, err.Error())
+		}
+		logger.ErrorContext(ctx, req.GetId(), "Price history error: "+err.Error())
+		return &historyResponse, status.Errorf(13, err.Error())
+	}
+
+	buckets, err := db.ListPriceBuckets(ctx, a.Prices, objId, from, to, interval)
+	if err != nil {
+		logger.ErrorContext(ctx, req.GetId(), "Price history error: "+err.Error())
+		if err == db.ErrTooManyPrices {
+			return &historyResponse, status.Errorf(11, err.Error())
 		}
+		return &historyResponse, status.Errorf(13, err.Error())
+	}
+
+	historyResponse.Id = req.GetId()
+	historyResponse.Interval = req.GetInterval()
+	if historyResponse.Interval == "" {
+		historyResponse.Interval = "raw"
+	}
+	for i := range buckets {
+		historyResponse.Buckets = append(historyResponse.Buckets, buckets[i].ToProtoBucket())
+	}
+
+	logger.InfoContext(ctx, req.GetId(), "Price history returned "+strconv.Itoa(len(buckets))+" buckets")
+	return &historyResponse, nil
+}
+
+func (a *AppServer) ListAuditEvents(ctx context.Context, req *proto.ListAuditEventsReq) (*proto.ListAuditEventsResp, error) {
+	logger.DebugContext(ctx, "", "Listing audit events received params "+req.String())
+	auditResponse := proto.ListAuditEventsResp{}
+
+	filter, err := helpers.ValidatorListAuditEvents(req)
+	if err != nil {
+		logger.ErrorContext(ctx, "", "Params to list audit events is invalid "+req.String())
+		return &auditResponse, status.Errorf(3, err.Error())
 	}
+
+	pageSize := req.GetPageSize()
+	if pageSize == 0 {
+		pageSize = helpers.DefaultPageSize
+	}
+
+	page := repositories.PageParams{
+		Size:  int64(pageSize),
+		Token: req.GetPageToken(),
+	}
+
+	events, nextToken, err := db.ListAuditEvents(ctx, a.Audit, filter, page)
+	if err != nil {
+		if err == db.ErrInvalidPageToken {
+			logger.ErrorContext(ctx, "", "Params to list audit events is invalid "+req.String())
+			return &auditResponse, status.Errorf(3, err.Error())
+		}
+		logger.ErrorContext(ctx, "", "Error to list audit events: "+err.Error())
+		return &auditResponse, status.Errorf(13, err.Error())
+	}
+
+	for i := range events {
+		auditResponse.Events = append(auditResponse.Events, events[i].ToProtoAuditEvent())
+	}
+	auditResponse.NextPageToken = nextToken
+
+	logger.InfoContext(ctx, "", "Audit events listed "+strconv.Itoa(len(events)))
+	return &auditResponse, nil
+}
+
+// Actor of mutation is the caller authenticated, the voter-id when auth is disabled or anonymous
+func actorFromContext(ctx context.Context) string {
+	actor, err := voterFromContext(ctx)
+	if err != nil {
+		return models.AnonymousActor
+	}
+	return actor
+}
+
+// Voter is the subject of identity or, when auth is disabled, the metadata voter-id of request
+func voterFromContext(ctx context.Context) (string, error) {
+	// authenticated caller votes as itself
+	identity, ok := auth.FromContext(ctx)
+	if ok {
+		return identity.Subject, nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", errors.New("metadata " + VoterMetadataKey + " not found")
+	}
+
+	values := md.Get(VoterMetadataKey)
+	if len(values) == 0 || strings.TrimSpace(values[0]) == "" {
+		return "", errors.New("metadata " + VoterMetadataKey + " not found")
+	}
+
+	return strings.TrimSpace(values[0]), nil
 }
@@ -1,19 +1,29 @@
 package controllers
 
 import (
+	"api-desafio-kvr/auth"
+	"api-desafio-kvr/helpers"
 	"api-desafio-kvr/models"
+	"api-desafio-kvr/observer"
 	"api-desafio-kvr/proto"
 	"api-desafio-kvr/repositories"
+	"api-desafio-kvr/repositories/cache"
 	"api-desafio-kvr/repositories/mongodb"
 	"context"
 	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func returnMockProtoModelCreateCrypto() proto.CreateCryptoReq {
@@ -104,19 +114,67 @@ func returnMockModelCryptoCurrencyEmpty() models.CryptoCurrency {
 	return models.CryptoCurrency{}
 }
 
+func returnMockDuplicateKeyError() error {
+	return mongo.WriteException{
+		WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000 duplicate key error"}},
+	}
+}
+
 type Mock_EndPointCryptos_MonitorVotesServer struct {
 	grpc.ServerStream
+	Ctx     context.Context
+	mu      sync.Mutex
 	Results []*proto.CryptoCurrency
+	SendErr error
 }
 
 func (mock *Mock_EndPointCryptos_MonitorVotesServer) Send(crypto *proto.CryptoCurrency) error {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	if mock.SendErr != nil {
+		return mock.SendErr
+	}
 	mock.Results = append(mock.Results, crypto)
 	return nil
 }
 
+func (mock *Mock_EndPointCryptos_MonitorVotesServer) Context() context.Context {
+	if mock.Ctx == nil {
+		return context.Background()
+	}
+	return mock.Ctx
+}
+
+func (mock *Mock_EndPointCryptos_MonitorVotesServer) Received() []*proto.CryptoCurrency {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	return append([]*proto.CryptoCurrency{}, mock.Results...)
+}
+
+func TestMain(m *testing.M) {
+	StartHub()
+	// History is not checked by most tests
+	mongodb.RecordPrice = func(ctx context.Context, coll mongodb.IMCollection, cryptoId primitive.ObjectID, price float64, at time.Time) (bool, error) {
+		return true, nil
+	}
+	mongodb.InsertAuditEvent = func(ctx context.Context, coll mongodb.IMCollection, event models.AuditEvent) error {
+		return nil
+	}
+	// Without database the writes of transaction run one by one
+	mongodb.WithTransaction = func(ctx context.Context, coll *mongo.Collection, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}
+	os.Exit(m.Run())
+}
+
+// Cache never hits, so every test reaches the mocks of database
+func returnMockAppServer() AppServer {
+	return AppServer{Cache: cache.NewNoop()}
+}
+
 // Testing crypto create with invalid name
 func TestCreateCryptoWithNameInvalid(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelCreateCrypto()
 	crypto.Name = "Crypto 123"
 
@@ -133,7 +191,7 @@ func TestCreateCryptoWithNameInvalid(t *testing.T) {
 
 // Testing crypto create with invalid asset_id
 func TestCreateCryptoWithAssetIdInvalid(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelCreateCrypto()
 	crypto.AssetId = "a"
 
@@ -150,7 +208,7 @@ func TestCreateCryptoWithAssetIdInvalid(t *testing.T) {
 
 // Testing crypto create with invalid price_usd
 func TestCreateCryptoWithPriceUsdInvalid(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelCreateCrypto()
 	crypto.PriceUsd = -5
 
@@ -167,10 +225,10 @@ func TestCreateCryptoWithPriceUsdInvalid(t *testing.T) {
 
 // Testing create crypto with error
 func TestCreateCryptoWithError(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelCreateCrypto()
 
-	mongodb.InsertCryptos = func(coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, error) {
+	mongodb.InsertCryptos = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, error) {
 		return models.CryptoCurrency{}, errors.New("test create error")
 	}
 
@@ -187,12 +245,31 @@ func TestCreateCryptoWithError(t *testing.T) {
 	defer cancel()
 }
 
+// Testing create crypto with asset_id already used
+func TestCreateCryptoWithAssetIdDuplicated(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelCreateCrypto()
+
+	mongodb.InsertCryptos = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, error) {
+		return models.CryptoCurrency{}, returnMockDuplicateKeyError()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	result, err := server.CreateCrypto(ctx, &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = AlreadyExists desc = asset_id already exists: TCR", err.Error())
+	require.Empty(t, result.Id)
+
+	defer cancel()
+}
+
 // Testing create crypto successful
 func TestCreateCryptoWithSuccess(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelCreateCrypto()
 
-	mongodb.InsertCryptos = func(coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, error) {
+	mongodb.InsertCryptos = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, error) {
 		return returnMockModelCryptoCurrency(), nil
 	}
 
@@ -213,7 +290,7 @@ func TestCreateCryptoWithSuccess(t *testing.T) {
 
 // Testing edit crypto with invalid id
 func TestEditCryptoWithIdInvalid(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelToEditCreateCrypto()
 	crypto.Id = "123abc"
 
@@ -230,7 +307,7 @@ func TestEditCryptoWithIdInvalid(t *testing.T) {
 
 // Testing edit crypto with invalid name
 func TestEditCryptoWithNameInvalid(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelToEditCreateCrypto()
 	crypto.Name = "Crypto 123"
 
@@ -247,7 +324,7 @@ func TestEditCryptoWithNameInvalid(t *testing.T) {
 
 // Testing edit crypto with invalid asset_id
 func TestEditCryptoWithAssetIdInvalid(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelToEditCreateCrypto()
 	crypto.AssetId = "a"
 
@@ -264,7 +341,7 @@ func TestEditCryptoWithAssetIdInvalid(t *testing.T) {
 
 // Testing edit crypto with invalid price_usd
 func TestEditCryptoWithPriceUsdInvalid(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelToEditCreateCrypto()
 	crypto.PriceUsd = -5
 
@@ -281,10 +358,14 @@ func TestEditCryptoWithPriceUsdInvalid(t *testing.T) {
 
 // Testing edit crypto with update error
 func TestEditCryptoWithUpdateCryptoError(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelToEditCreateCrypto()
 
-	mongodb.UpdateCrypto = func(coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return returnMockModelCryptoCurrency(), nil
+	}
+
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
 		return models.CryptoCurrency{Id: crypto.Id}, 0, errors.New("test update error")
 	}
 
@@ -301,16 +382,41 @@ func TestEditCryptoWithUpdateCryptoError(t *testing.T) {
 	defer cancel()
 }
 
+// Testing edit crypto with asset_id already used by other crypto
+func TestEditCryptoWithAssetIdDuplicated(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToEditCreateCrypto()
+
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return returnMockModelCryptoCurrency(), nil
+	}
+
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		return crypto, 0, returnMockDuplicateKeyError()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	result, err := server.EditCrypto(ctx, &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = AlreadyExists desc = asset_id already exists: TCE", err.Error())
+	require.Empty(t, result.Id)
+
+	defer cancel()
+}
+
 // Testing edit crypto with getbyid error
 func TestEditCryptoWithGetByIdError(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelToEditCreateCrypto()
 
-	mongodb.UpdateCrypto = func(coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+	updated := false
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		updated = true
 		return models.CryptoCurrency{Id: crypto.Id}, 1, nil
 	}
 
-	mongodb.GetById = func(coll mongodb.IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
 		return returnMockModelCryptoCurrencyEmpty(), errors.New("test getbyid error")
 	}
 
@@ -318,7 +424,8 @@ func TestEditCryptoWithGetByIdError(t *testing.T) {
 	result, err := server.EditCrypto(ctx, &crypto)
 
 	require.NotNil(t, err)
-	require.Equal(t, "rpc error: code = NotFound desc = test getbyid error", err.Error())
+	require.Equal(t, "rpc error: code = Internal desc = test getbyid error", err.Error())
+	require.False(t, updated)
 	require.Empty(t, result.Id)
 	require.Empty(t, result.Votes)
 	require.Empty(t, result.CreatedAt)
@@ -329,41 +436,45 @@ func TestEditCryptoWithGetByIdError(t *testing.T) {
 
 // Testing edit crypto successful
 func TestEditCryptoWithSuccess(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelToEditCreateCrypto()
 
-	mongodb.UpdateCrypto = func(coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
-		return models.CryptoCurrency{Id: crypto.Id}, 1, nil
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return returnMockModelCryptoCurrency(), nil
 	}
 
-	mongodb.GetById = func(coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
-		cryptoId, _ := primitive.ObjectIDFromHex(crypto.Id)
+	// response is the document returned by the update, not a later read
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
 		return models.CryptoCurrency{
-			Id:       cryptoId,
-			Name:     crypto.Name,
-			AssetId:  crypto.AssetId,
-			PriceUsd: crypto.PriceUsd,
-		}, nil
+			Id:        crypto.Id,
+			Name:      crypto.Name,
+			AssetId:   crypto.AssetId,
+			PriceUsd:  crypto.PriceUsd,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Version:   2,
+		}, 1, nil
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	result, err := server.EditCrypto(ctx, &crypto)
 
 	require.Nil(t, err)
-	require.NotEmpty(t, result.Id)
+	require.Equal(t, crypto.Id, result.Id)
 	require.Equal(t, crypto.Name, result.Name)
-	require.Equal(t, crypto.AssetId, result.AssetId)
+	require.Equal(t, "TCE", result.AssetId)
 	require.Equal(t, crypto.PriceUsd, result.PriceUsd)
 	require.Empty(t, result.Votes)
 	require.NotEmpty(t, result.CreatedAt)
 	require.NotEmpty(t, result.UpdatedAt)
+	require.Equal(t, int64(2), result.Version)
 
 	defer cancel()
 }
 
 // Testing delete crypto with invalid id
 func TestDeleteCryptoWithIdInvalid(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelToDeleteCrypto()
 	crypto.Id = "123abc"
 
@@ -378,11 +489,15 @@ func TestDeleteCryptoWithIdInvalid(t *testing.T) {
 
 // Testing delete crypto with deletebyid did not find document
 func TestDeleteCryptoWithDeleteByIdErrorErrNoDocuments(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelToDeleteCrypto()
 
-	mongodb.DeleteById = func(coll mongodb.IMCollection, id primitive.ObjectID) (primitive.ObjectID, error) {
-		return id, mongo.ErrNoDocuments
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return returnMockModelCryptoCurrency(), nil
+	}
+
+	mongodb.DeleteById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return models.CryptoCurrency{}, mongo.ErrNoDocuments
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
@@ -396,11 +511,15 @@ func TestDeleteCryptoWithDeleteByIdErrorErrNoDocuments(t *testing.T) {
 
 // Testing delete crypto with deletebyid error
 func TestDeleteCryptoWithDeleteByIdError(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelToDeleteCrypto()
 
-	mongodb.DeleteById = func(coll mongodb.IMCollection, id primitive.ObjectID) (primitive.ObjectID, error) {
-		return id, errors.New("testing DeleteCrypo with error in DeleteById")
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return returnMockModelCryptoCurrency(), nil
+	}
+
+	mongodb.DeleteById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return models.CryptoCurrency{}, errors.New("testing DeleteCrypo with error in DeleteById")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
@@ -414,11 +533,15 @@ func TestDeleteCryptoWithDeleteByIdError(t *testing.T) {
 
 // Testing delete crypto successful
 func TestDeleteCryptoWithSuccess(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelToDeleteCrypto()
 
-	mongodb.DeleteById = func(coll mongodb.IMCollection, id primitive.ObjectID) (primitive.ObjectID, error) {
-		return id, nil
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return returnMockModelCryptoCurrency(), nil
+	}
+
+	mongodb.DeleteById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return models.CryptoCurrency{Id: id}, nil
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
@@ -433,7 +556,7 @@ func TestDeleteCryptoWithSuccess(t *testing.T) {
 
 // Testing find crypto with invalid id
 func TestFindCryptoWithIdInvalid(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelToFindCrypto()
 	crypto.Id = "123abc"
 
@@ -448,10 +571,10 @@ func TestFindCryptoWithIdInvalid(t *testing.T) {
 
 // Testing find crypto with deletebyid did not find document
 func TestFindCryptoWithDeleteByIdErrorErrNoDocuments(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelToFindCrypto()
 
-	mongodb.GetById = func(coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
 		return returnMockModelCryptoCurrency(), mongo.ErrNoDocuments
 	}
 
@@ -466,10 +589,10 @@ func TestFindCryptoWithDeleteByIdErrorErrNoDocuments(t *testing.T) {
 
 // Testing find crypto with deletebyid error
 func TestFindCryptoWithDeleteByIdError(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelToFindCrypto()
 
-	mongodb.GetById = func(coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
 		return returnMockModelCryptoCurrency(), errors.New("testing FindCrypo with error in GetById")
 	}
 
@@ -484,12 +607,12 @@ func TestFindCryptoWithDeleteByIdError(t *testing.T) {
 
 // Testing find crypto successful
 func TestFindCryptoWithSuccess(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	crypto := returnMockProtoModelToFindCrypto()
 	mockResponse := returnMockModelCryptoCurrency()
 	crypto.Id = mockResponse.Id.Hex()
 
-	mongodb.GetById = func(coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
 		return mockResponse, nil
 	}
 
@@ -508,9 +631,100 @@ func TestFindCryptoWithSuccess(t *testing.T) {
 	defer cancel()
 }
 
+// Testing concurrent find crypto of same id without cache finds once in database
+func TestFindCryptoWithConcurrentCacheMiss(t *testing.T) {
+	server := returnMockAppServer()
+	mockResponse := returnMockModelCryptoCurrency()
+	crypto := proto.FindCryptoReq{Id: mockResponse.Id.Hex()}
+
+	var calls int32
+	release := make(chan struct{})
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return mockResponse, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	requests := 10
+	results := make(chan *proto.CryptoCurrency, requests)
+	for i := 0; i < requests; i++ {
+		go func() {
+			result, err := server.FindCrypto(ctx, &crypto)
+			require.Nil(t, err)
+			results <- result
+		}()
+	}
+
+	// waits the requests be in the same load
+	time.Sleep(time.Millisecond * 200)
+	close(release)
+
+	for i := 0; i < requests; i++ {
+		result := <-results
+		require.Equal(t, mockResponse.Id.Hex(), result.Id)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+// Testing find crypto by asset with invalid asset_id
+func TestFindCryptoByAssetWithAssetIdInvalid(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := proto.FindCryptoByAssetReq{AssetId: "a"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	_, err := server.FindCryptoByAsset(ctx, &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = InvalidArgument desc = asset_id is invalid: a", err.Error())
+
+	defer cancel()
+}
+
+// Testing find crypto by asset did not find document
+func TestFindCryptoByAssetWithGetByAssetErrNoDocuments(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := proto.FindCryptoByAssetReq{AssetId: "btc"}
+
+	mongodb.GetByAsset = func(ctx context.Context, coll mongodb.IMCollection, assetId string) (models.CryptoCurrency, error) {
+		return models.CryptoCurrency{}, mongo.ErrNoDocuments
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	_, err := server.FindCryptoByAsset(ctx, &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = NotFound desc = mongo: no documents in result", err.Error())
+
+	defer cancel()
+}
+
+// Testing find crypto by asset successful
+func TestFindCryptoByAssetWithSuccess(t *testing.T) {
+	server := returnMockAppServer()
+	mockResponse := returnMockModelCryptoCurrency()
+	crypto := proto.FindCryptoByAssetReq{AssetId: "tcr"}
+
+	mongodb.GetByAsset = func(ctx context.Context, coll mongodb.IMCollection, assetId string) (models.CryptoCurrency, error) {
+		return mockResponse, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	result, err := server.FindCryptoByAsset(ctx, &crypto)
+
+	require.Nil(t, err)
+	require.Equal(t, mockResponse.Id.Hex(), result.Id)
+	require.Equal(t, mockResponse.AssetId, result.AssetId)
+	require.NotEmpty(t, result.CreatedAt)
+
+	defer cancel()
+}
+
 // Testing list all cryptos with sort params invalid
 func TestListAllCryptosWithSortParamsInvalid(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	sortParams := returnMockProtoModelToSortCryptos()
 	sortParams.FieldSort = "a"
 
@@ -525,11 +739,11 @@ func TestListAllCryptosWithSortParamsInvalid(t *testing.T) {
 
 // Testing list all cryptos with listall error
 func TestListAllCryptosWithListAllError(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	sortParams := returnMockProtoModelToSortCryptos()
 
-	mongodb.ListAll = func(coll mongodb.IMCollection, sort repositories.SortParams) (result []models.CryptoCurrency, err error) {
-		return []models.CryptoCurrency{}, errors.New("testing ListAllCryptos with error in ListAll")
+	mongodb.ListPage = func(ctx context.Context, coll mongodb.IMCollection, sort repositories.SortParams, page repositories.PageParams) ([]models.CryptoCurrency, string, error) {
+		return []models.CryptoCurrency{}, "", errors.New("testing ListAllCryptos with error in ListAll")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
@@ -543,13 +757,17 @@ func TestListAllCryptosWithListAllError(t *testing.T) {
 
 // Testing list all cryptos with listall empty
 func TestListAllCryptosWithListAllEmpty(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	sortParams := returnMockProtoModelToSortCryptos()
 	mockCryptoEmpty := proto.ListCryptosResp{}
 	mockCryptoEmpty.Crypto = []*proto.CryptoCurrency{}
 
-	mongodb.ListAll = func(coll mongodb.IMCollection, sort repositories.SortParams) (result []models.CryptoCurrency, err error) {
-		return []models.CryptoCurrency{}, nil
+	mongodb.ListPage = func(ctx context.Context, coll mongodb.IMCollection, sort repositories.SortParams, page repositories.PageParams) ([]models.CryptoCurrency, string, error) {
+		return []models.CryptoCurrency{}, "", nil
+	}
+
+	mongodb.CountDocuments = func(ctx context.Context, coll mongodb.IMCollection) (int64, error) {
+		return 0, nil
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
@@ -563,11 +781,15 @@ func TestListAllCryptosWithListAllEmpty(t *testing.T) {
 
 // Testing list all cryptos successful
 func TestListAllCryptosWithSuccess(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 	sortParams := returnMockProtoModelToSortCryptos()
 
-	mongodb.ListAll = func(coll mongodb.IMCollection, sort repositories.SortParams) (result []models.CryptoCurrency, err error) {
-		return returnMockDbListAll(), nil
+	mongodb.ListPage = func(ctx context.Context, coll mongodb.IMCollection, sort repositories.SortParams, page repositories.PageParams) ([]models.CryptoCurrency, string, error) {
+		return returnMockDbListAll(), "", nil
+	}
+
+	mongodb.CountDocuments = func(ctx context.Context, coll mongodb.IMCollection) (int64, error) {
+		return 2, nil
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
@@ -575,223 +797,252 @@ func TestListAllCryptosWithSuccess(t *testing.T) {
 
 	require.Nil(t, err)
 	require.Equal(t, 2, len(result.Crypto))
+	require.Equal(t, int64(2), result.TotalCount)
+	require.Empty(t, result.NextPageToken)
 
 	defer cancel()
 }
 
-// Testing upvote with invalid id
-func TestUpvoteWithIdInvalid(t *testing.T) {
-	server := AppServer{}
-	crypto := returnMockProtoModelToVote()
-	crypto.Id = "123abc"
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	_, err := server.Upvote(ctx, &crypto)
-
-	require.NotNil(t, err)
-	require.Equal(t, "rpc error: code = InvalidArgument desc = id is invalid: 123abc err: the provided hex string is not a valid ObjectID", err.Error())
-
-	defer cancel()
-}
+// Testing list all cryptos with page size and next page
+func TestListAllCryptosWithNextPage(t *testing.T) {
+	server := returnMockAppServer()
+	sortParams := returnMockProtoModelToSortCryptos()
+	sortParams.PageSize = 2
+	sortParams.PageToken = "first-page-test"
 
-// Testing upvote with updatecrypto error
-func TestUpvoteWithUpdateCryptoError(t *testing.T) {
-	server := AppServer{}
-	crypto := returnMockProtoModelToVote()
+	var pageReceived repositories.PageParams
+	mongodb.ListPage = func(ctx context.Context, coll mongodb.IMCollection, sort repositories.SortParams, page repositories.PageParams) ([]models.CryptoCurrency, string, error) {
+		pageReceived = page
+		return returnMockDbListAll(), "next-page-test", nil
+	}
 
-	mongodb.UpdateCrypto = func(coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
-		return models.CryptoCurrency{}, 0, errors.New("testing Upvote with error in UpdateCrypto")
+	mongodb.CountDocuments = func(ctx context.Context, coll mongodb.IMCollection) (int64, error) {
+		return 5, nil
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	_, err := server.Upvote(ctx, &crypto)
+	result, err := server.ListAllCryptos(ctx, &sortParams)
 
-	require.NotNil(t, err)
-	require.Equal(t, "rpc error: code = Internal desc = testing Upvote with error in UpdateCrypto", err.Error())
+	require.Nil(t, err)
+	require.Equal(t, int64(2), pageReceived.Size)
+	require.Equal(t, "first-page-test", pageReceived.Token)
+	require.Equal(t, 2, len(result.Crypto))
+	require.Equal(t, int64(5), result.TotalCount)
+	require.Equal(t, "next-page-test", result.NextPageToken)
 
 	defer cancel()
 }
 
-// Testing upvote with updatecrypto matchedCount value is zero and getbyid error
-func TestUpvoteWithUpdateCryptoMatchedCountZeroAndGetByIdError(t *testing.T) {
-	server := AppServer{}
-	crypto := returnMockProtoModelToVote()
+// Testing list all cryptos without page_size and page_token lists all, with page_token the default size
+func TestListAllCryptosWithoutPageSize(t *testing.T) {
+	server := returnMockAppServer()
+	sortParams := returnMockProtoModelToSortCryptos()
 
-	mongodb.UpdateCrypto = func(coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
-		return crypto, 0, nil
+	var pageReceived repositories.PageParams
+	mongodb.ListPage = func(ctx context.Context, coll mongodb.IMCollection, sort repositories.SortParams, page repositories.PageParams) ([]models.CryptoCurrency, string, error) {
+		pageReceived = page
+		return returnMockDbListAll(), "", nil
 	}
 
-	mongodb.GetById = func(coll mongodb.IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
-		return models.CryptoCurrency{}, errors.New("testing Upvote with error in GetById")
+	mongodb.CountDocuments = func(ctx context.Context, coll mongodb.IMCollection) (int64, error) {
+		return 2, nil
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	_, err := server.Upvote(ctx, &crypto)
+	defer cancel()
 
-	require.NotNil(t, err)
-	require.Equal(t, "rpc error: code = NotFound desc = testing Upvote with error in GetById", err.Error())
+	result, err := server.ListAllCryptos(ctx, &sortParams)
+	require.Nil(t, err)
+	require.Equal(t, int64(0), pageReceived.Size)
+	require.Equal(t, 2, len(result.Crypto))
+	require.Empty(t, result.NextPageToken)
 
-	defer cancel()
+	sortParams.PageToken = "next-page-test"
+	_, err = server.ListAllCryptos(ctx, &sortParams)
+	require.Nil(t, err)
+	require.Equal(t, int64(helpers.DefaultPageSize), pageReceived.Size)
 }
 
-// Testing upvote with updatecrypto matchedCount value is zero and getbyid empty
-func TestUpvoteWithUpdateCryptoMatchedCountZeroButGetByIdCryptoNotExist(t *testing.T) {
-	server := AppServer{}
-	crypto := returnMockProtoModelToVote()
-
-	mongodb.UpdateCrypto = func(coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
-		return crypto, 0, nil
-	}
+// Testing list all cryptos with invalid page token
+func TestListAllCryptosWithPageTokenInvalid(t *testing.T) {
+	server := returnMockAppServer()
+	sortParams := returnMockProtoModelToSortCryptos()
+	sortParams.PageToken = "invalid-token-test"
 
-	mongodb.GetById = func(coll mongodb.IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
-		return models.CryptoCurrency{}, nil
+	mongodb.ListPage = func(ctx context.Context, coll mongodb.IMCollection, sort repositories.SortParams, page repositories.PageParams) ([]models.CryptoCurrency, string, error) {
+		return []models.CryptoCurrency{}, "", mongodb.ErrInvalidPageToken
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	_, err := server.Upvote(ctx, &crypto)
+	_, err := server.ListAllCryptos(ctx, &sortParams)
 
 	require.NotNil(t, err)
-	require.Equal(t, "rpc error: code = Internal desc = crypto not exist", err.Error())
+	require.Equal(t, "rpc error: code = InvalidArgument desc = page_token is invalid", err.Error())
 
 	defer cancel()
 }
 
-// Testing upvote successful
-func TestUpvoteWithSuccess(t *testing.T) {
-	server := AppServer{}
-	crypto := returnMockProtoModelToVote()
-
-	mongodb.UpdateCrypto = func(coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
-		return crypto, 1, nil
-	}
-
-	mongodb.GetById = func(coll mongodb.IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
-		return models.CryptoCurrency{
-			Votes: 1,
-		}, nil
-	}
+// Testing search cryptos with invalid price range
+func TestSearchCryptosWithPriceRangeInvalid(t *testing.T) {
+	server := returnMockAppServer()
+	minPrice, maxPrice := 10.0, 1.0
+	search := proto.SearchCryptosReq{MinPriceUsd: &minPrice, MaxPriceUsd: &maxPrice}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	result, err := server.Upvote(ctx, &crypto)
+	_, err := server.SearchCryptos(ctx, &search)
 
-	require.Nil(t, err)
-	require.Equal(t, crypto.Id, result.Id)
-	require.Equal(t, "registered upvote successful", result.Message)
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = InvalidArgument desc = price range is invalid: min_price_usd is bigger than max_price_usd", err.Error())
 
 	defer cancel()
 }
 
-// Testing downvote with invalid id
-func TestDownvoteWithIdInvalid(t *testing.T) {
-	server := AppServer{}
-	crypto := returnMockProtoModelToVote()
-	crypto.Id = "123abc"
+// Testing search cryptos with search error
+func TestSearchCryptosWithSearchError(t *testing.T) {
+	server := returnMockAppServer()
+	search := proto.SearchCryptosReq{Name: "bit"}
+
+	mongodb.Search = func(ctx context.Context, coll mongodb.IMCollection, search repositories.SearchParams, sort repositories.SortParams, page repositories.PageParams) ([]models.CryptoCurrency, string, error) {
+		return []models.CryptoCurrency{}, "", errors.New("testing SearchCryptos with error in Search")
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	_, err := server.Downvote(ctx, &crypto)
+	_, err := server.SearchCryptos(ctx, &search)
 
 	require.NotNil(t, err)
-	require.Equal(t, "rpc error: code = InvalidArgument desc = id is invalid: 123abc err: the provided hex string is not a valid ObjectID", err.Error())
+	require.Equal(t, "rpc error: code = Internal desc = testing SearchCryptos with error in Search", err.Error())
 
 	defer cancel()
 }
 
-// Testing downvote with updatecrypto error
-func TestDownvoteWithUpdateCryptoError(t *testing.T) {
-	server := AppServer{}
-	crypto := returnMockProtoModelToVote()
+// Testing search cryptos successful
+func TestSearchCryptosWithSuccess(t *testing.T) {
+	server := returnMockAppServer()
+	minVotes := int32(5)
+	search := proto.SearchCryptosReq{Name: "test", NamePrefix: true, AssetId: "tn1", MinVotes: &minVotes, FieldSort: "votes"}
+
+	var searchReceived repositories.SearchParams
+	var sortReceived repositories.SortParams
+	var pageReceived repositories.PageParams
+	mongodb.Search = func(ctx context.Context, coll mongodb.IMCollection, search repositories.SearchParams, sort repositories.SortParams, page repositories.PageParams) ([]models.CryptoCurrency, string, error) {
+		searchReceived = search
+		sortReceived = sort
+		pageReceived = page
+		return returnMockDbListAll(), "", nil
+	}
 
-	mongodb.UpdateCrypto = func(coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
-		return models.CryptoCurrency{}, 0, errors.New("testing Downvote with error in UpdateCrypto")
+	mongodb.CountSearch = func(ctx context.Context, coll mongodb.IMCollection, search repositories.SearchParams) (int64, error) {
+		return 2, nil
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	_, err := server.Downvote(ctx, &crypto)
+	result, err := server.SearchCryptos(ctx, &search)
 
-	require.NotNil(t, err)
-	require.Equal(t, "rpc error: code = Internal desc = testing Downvote with error in UpdateCrypto", err.Error())
+	require.Nil(t, err)
+	require.Equal(t, 2, len(result.Crypto))
+	require.Equal(t, int64(2), result.TotalCount)
+	require.Empty(t, result.NextPageToken)
+	require.Equal(t, "test", searchReceived.Name)
+	require.True(t, searchReceived.NamePrefix)
+	require.Equal(t, "tn1", searchReceived.AssetId)
+	require.Equal(t, minVotes, *searchReceived.MinVotes)
+	require.Nil(t, searchReceived.MaxVotes)
+	require.Equal(t, int64(helpers.DefaultPageSize), pageReceived.Size)
+	require.Equal(t, "votes", sortReceived.Field)
 
 	defer cancel()
 }
 
-// Testing downvote with updatecrypto matchedCount value is zero and getbyid error
-func TestDownvoteWithUpdateCryptoMatchedCountZeroAndGetByIdError(t *testing.T) {
-	server := AppServer{}
-	crypto := returnMockProtoModelToVote()
+// Testing search cryptos with page size and next page, total_count counts all pages
+func TestSearchCryptosWithNextPage(t *testing.T) {
+	server := returnMockAppServer()
+	search := proto.SearchCryptosReq{Name: "b", PageSize: 2, PageToken: "first-page-test"}
 
-	mongodb.UpdateCrypto = func(coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
-		return crypto, 0, nil
+	var pageReceived repositories.PageParams
+	mongodb.Search = func(ctx context.Context, coll mongodb.IMCollection, search repositories.SearchParams, sort repositories.SortParams, page repositories.PageParams) ([]models.CryptoCurrency, string, error) {
+		pageReceived = page
+		return returnMockDbListAll(), "next-page-test", nil
 	}
 
-	mongodb.GetById = func(coll mongodb.IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
-		return models.CryptoCurrency{}, errors.New("testing Downvote with error in GetById")
+	mongodb.CountSearch = func(ctx context.Context, coll mongodb.IMCollection, search repositories.SearchParams) (int64, error) {
+		return 5, nil
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	_, err := server.Downvote(ctx, &crypto)
-
-	require.NotNil(t, err)
-	require.Equal(t, "rpc error: code = NotFound desc = testing Downvote with error in GetById", err.Error())
-
 	defer cancel()
-}
 
-// Testing downvote with vote equal zero
-func TestDownvoteWithVoteEqualZero(t *testing.T) {
-	server := AppServer{}
-	crypto := returnMockProtoModelToVote()
+	result, err := server.SearchCryptos(ctx, &search)
+	require.Nil(t, err)
+	require.Equal(t, repositories.PageParams{Size: 2, Token: "first-page-test"}, pageReceived)
+	require.Equal(t, 2, len(result.Crypto))
+	require.Equal(t, int64(5), result.TotalCount)
+	require.Equal(t, "next-page-test", result.NextPageToken)
+}
 
-	mongodb.UpdateCrypto = func(coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
-		return crypto, 0, nil
-	}
+// Testing search cryptos with invalid page token
+func TestSearchCryptosWithPageTokenInvalid(t *testing.T) {
+	server := returnMockAppServer()
+	search := proto.SearchCryptosReq{Name: "b", PageToken: "invalid-token-test"}
 
-	mongodb.GetById = func(coll mongodb.IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
-		return models.CryptoCurrency{}, nil
+	mongodb.Search = func(ctx context.Context, coll mongodb.IMCollection, search repositories.SearchParams, sort repositories.SortParams, page repositories.PageParams) ([]models.CryptoCurrency, string, error) {
+		return []models.CryptoCurrency{}, "", mongodb.ErrInvalidPageToken
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	_, err := server.Downvote(ctx, &crypto)
+	defer cancel()
 
+	_, err := server.SearchCryptos(ctx, &search)
 	require.NotNil(t, err)
-	require.Equal(t, "rpc error: code = Internal desc = unchanged crypto because vote = 0", err.Error())
-
-	defer cancel()
+	require.Equal(t, "rpc error: code = InvalidArgument desc = page_token is invalid", err.Error())
 }
 
-// Testing upvote successful
-func TestDownvoteWithSuccess(t *testing.T) {
-	server := AppServer{}
-	crypto := returnMockProtoModelToVote()
+// Testing search cryptos with error in count
+func TestSearchCryptosWithCountError(t *testing.T) {
+	server := returnMockAppServer()
+	search := proto.SearchCryptosReq{Name: "b"}
 
-	mongodb.UpdateCrypto = func(coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
-		return crypto, 1, nil
+	mongodb.Search = func(ctx context.Context, coll mongodb.IMCollection, search repositories.SearchParams, sort repositories.SortParams, page repositories.PageParams) ([]models.CryptoCurrency, string, error) {
+		return returnMockDbListAll(), "", nil
 	}
 
-	mongodb.GetById = func(coll mongodb.IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
-		return models.CryptoCurrency{
-			Votes: 0,
-		}, nil
+	mongodb.CountSearch = func(ctx context.Context, coll mongodb.IMCollection, search repositories.SearchParams) (int64, error) {
+		return 0, errors.New("testing SearchCryptos with error in CountSearch")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	result, err := server.Downvote(ctx, &crypto)
+	defer cancel()
 
-	require.Nil(t, err)
-	require.Equal(t, crypto.Id, result.Id)
-	require.Equal(t, "registered downvote successful", result.Message)
+	_, err := server.SearchCryptos(ctx, &search)
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = Internal desc = testing SearchCryptos with error in CountSearch", err.Error())
+}
 
-	defer cancel()
+// Help function to create context with voter of request
+func contextWithVoter(voterId string) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(VoterMetadataKey, voterId))
+	return ctx, cancel
 }
 
-// Testing monitor votes with invalid id
-func TestMonitorVotesWithIdInvalid(t *testing.T) {
-	server := AppServer{}
-	crypto := returnMockProtoModelToMonitorVotes()
+// Help function to mock crypto found and vote saved
+func mockVoteFound(previous int32) {
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return models.CryptoCurrency{Id: id, Votes: 1}, nil
+	}
+
+	mongodb.SetVote = func(ctx context.Context, coll mongodb.IMCollection, voterId string, cryptoId primitive.ObjectID, value int32) (int32, error) {
+		return previous, nil
+	}
+}
+
+// Testing upvote with invalid id
+func TestUpvoteWithIdInvalid(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
 	crypto.Id = "123abc"
-	mockStream := Mock_EndPointCryptos_MonitorVotesServer{}
 
-	_, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	err := server.MonitorVotes(&crypto, &mockStream)
+	ctx, cancel := contextWithVoter("voter-test")
+	_, err := server.Upvote(ctx, &crypto)
 
 	require.NotNil(t, err)
 	require.Equal(t, "rpc error: code = InvalidArgument desc = id is invalid: 123abc err: the provided hex string is not a valid ObjectID", err.Error())
@@ -799,91 +1050,1451 @@ func TestMonitorVotesWithIdInvalid(t *testing.T) {
 	defer cancel()
 }
 
-// Help function to TestMonitorVotesWithGetByIdError and TestMonitorVotesWithSuccess
-func mockUpdateToStream(server AppServer) proto.VoteReq {
-	cryptoToUpVote := returnMockProtoModelToVote()
-
-	mongodb.UpdateCrypto = func(coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
-		return crypto, 1, nil
-	}
-
-	mongodb.GetById = func(coll mongodb.IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
-		return models.CryptoCurrency{}, nil
-	}
+// Testing upvote without voter in metadata
+func TestUpvoteWithoutVoter(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	_, _ = server.Upvote(ctx, &cryptoToUpVote)
+	_, err := server.Upvote(ctx, &crypto)
 
-	defer cancel()
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = Unauthenticated desc = metadata voter-id not found", err.Error())
 
-	return cryptoToUpVote
+	defer cancel()
 }
 
-// Testing monitor votes with getbyid error
-func TestMonitorVotesWithGetByIdError(t *testing.T) {
-	server := AppServer{}
-
-	cryptoMonitor := returnMockProtoModelToMonitorVotes()
-	mockStream := Mock_EndPointCryptos_MonitorVotesServer{}
+// Testing upvote of authenticated caller votes with its subject, not with metadata voter-id
+func TestUpvoteWithIdentity(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
+	mockVoteFound(models.VoteNone)
 
-	StartChanToStream()
-	SetObserver = func(id string) {
-		observer <- cryptoMonitor.Id
+	var voter string
+	mongodb.SetVote = func(ctx context.Context, coll mongodb.IMCollection, voterId string, cryptoId primitive.ObjectID, value int32) (int32, error) {
+		voter = voterId
+		return models.VoteNone, nil
 	}
 
-	cryptoReceivedUpVote := mockUpdateToStream(server)
-	cryptoMonitor.Id = cryptoReceivedUpVote.Id
+	ctx, cancel := contextWithVoter("voter-metadata")
+	defer cancel()
+	ctx = auth.ContextWithIdentity(ctx, auth.Identity{Subject: "voter-token", Method: auth.MethodJWT})
 
-	mongodb.GetById = func(coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
-		return models.CryptoCurrency{}, errors.New("testing MonitorVotes with error in GetById")
+	_, err := server.Upvote(ctx, &crypto)
+
+	require.Nil(t, err)
+	require.Equal(t, "voter-token", voter)
+}
+
+// Testing upvote with crypto not found
+func TestUpvoteWithGetByIdErrNoDocuments(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
+
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
+		return models.CryptoCurrency{}, mongo.ErrNoDocuments
+	}
+
+	ctx, cancel := contextWithVoter("voter-test")
+	_, err := server.Upvote(ctx, &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = NotFound desc = mongo: no documents in result", err.Error())
+
+	defer cancel()
+}
+
+// Testing upvote with getbyid error
+func TestUpvoteWithGetByIdError(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
+
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
+		return models.CryptoCurrency{}, errors.New("testing Upvote with error in GetById")
+	}
+
+	ctx, cancel := contextWithVoter("voter-test")
+	_, err := server.Upvote(ctx, &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = Internal desc = testing Upvote with error in GetById", err.Error())
+
+	defer cancel()
+}
+
+// Testing upvote with setvote error
+func TestUpvoteWithSetVoteError(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
+	mockVoteFound(models.VoteNone)
+
+	mongodb.SetVote = func(ctx context.Context, coll mongodb.IMCollection, voterId string, cryptoId primitive.ObjectID, value int32) (int32, error) {
+		return models.VoteNone, errors.New("testing Upvote with error in SetVote")
+	}
+
+	ctx, cancel := contextWithVoter("voter-test")
+	_, err := server.Upvote(ctx, &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = Internal desc = testing Upvote with error in SetVote", err.Error())
+
+	defer cancel()
+}
+
+// Testing upvote with updatecrypto error undo the vote
+func TestUpvoteWithUpdateCryptoError(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
+	mockVoteFound(models.VoteNone)
+
+	votesSaved := []int32{}
+	mongodb.SetVote = func(ctx context.Context, coll mongodb.IMCollection, voterId string, cryptoId primitive.ObjectID, value int32) (int32, error) {
+		votesSaved = append(votesSaved, value)
+		return models.VoteNone, nil
+	}
+
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		return models.CryptoCurrency{}, 0, errors.New("testing Upvote with error in UpdateCrypto")
+	}
+
+	ctx, cancel := contextWithVoter("voter-test")
+	_, err := server.Upvote(ctx, &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = Internal desc = testing Upvote with error in UpdateCrypto", err.Error())
+	require.Equal(t, []int32{models.VoteUp, models.VoteNone}, votesSaved)
+
+	defer cancel()
+}
+
+// Testing upvote with updatecrypto matchedCount value is zero because crypto was deleted
+func TestUpvoteWithUpdateCryptoMatchedCountZeroButCryptoNotExist(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
+	mockVoteFound(models.VoteNone)
+
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		return crypto, 0, nil
+	}
+
+	ctx, cancel := contextWithVoter("voter-test")
+	_, err := server.Upvote(ctx, &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = NotFound desc = crypto not exist", err.Error())
+
+	defer cancel()
+}
+
+// Testing upvote again is idempotent
+func TestUpvoteWithVoteAlreadyRegistered(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
+	mockVoteFound(models.VoteUp)
+
+	updated := false
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		updated = true
+		return crypto, 1, nil
+	}
+
+	ctx, cancel := contextWithVoter("voter-test")
+	result, err := server.Upvote(ctx, &crypto)
+
+	require.Nil(t, err)
+	require.False(t, updated)
+	require.Equal(t, crypto.Id, result.Id)
+	require.Equal(t, "upvote already registered", result.Message)
+
+	defer cancel()
+}
+
+// Testing upvote successful
+func TestUpvoteWithSuccess(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
+	mockVoteFound(models.VoteNone)
+
+	var delta int32
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		delta = crypto.VotesDelta
+		return crypto, 1, nil
+	}
+
+	ctx, cancel := contextWithVoter("voter-test")
+	result, err := server.Upvote(ctx, &crypto)
+
+	require.Nil(t, err)
+	require.Equal(t, int32(1), delta)
+	require.Equal(t, crypto.Id, result.Id)
+	require.Equal(t, "registered upvote successful", result.Message)
+
+	defer cancel()
+}
+
+// Testing downvote with invalid id
+func TestDownvoteWithIdInvalid(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
+	crypto.Id = "123abc"
+
+	ctx, cancel := contextWithVoter("voter-test")
+	_, err := server.Downvote(ctx, &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = InvalidArgument desc = id is invalid: 123abc err: the provided hex string is not a valid ObjectID", err.Error())
+
+	defer cancel()
+}
+
+// Testing downvote with updatecrypto error
+func TestDownvoteWithUpdateCryptoError(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
+	mockVoteFound(models.VoteNone)
+
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		return models.CryptoCurrency{}, 0, errors.New("testing Downvote with error in UpdateCrypto")
+	}
+
+	ctx, cancel := contextWithVoter("voter-test")
+	_, err := server.Downvote(ctx, &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = Internal desc = testing Downvote with error in UpdateCrypto", err.Error())
+
+	defer cancel()
+}
+
+// Testing downvote after upvote moves votes by two
+func TestDownvoteWithPreviousUpvote(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
+	mockVoteFound(models.VoteUp)
+
+	var delta int32
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		delta = crypto.VotesDelta
+		return crypto, 1, nil
 	}
 
+	ctx, cancel := contextWithVoter("voter-test")
+	result, err := server.Downvote(ctx, &crypto)
+
+	require.Nil(t, err)
+	require.Equal(t, int32(-2), delta)
+	require.Equal(t, "registered downvote successful", result.Message)
+
+	defer cancel()
+}
+
+// Testing downvote successful
+func TestDownvoteWithSuccess(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
+	mockVoteFound(models.VoteNone)
+
+	var delta int32
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		delta = crypto.VotesDelta
+		return crypto, 1, nil
+	}
+
+	ctx, cancel := contextWithVoter("voter-test")
+	result, err := server.Downvote(ctx, &crypto)
+
+	require.Nil(t, err)
+	require.Equal(t, int32(-1), delta)
+	require.Equal(t, crypto.Id, result.Id)
+	require.Equal(t, "registered downvote successful", result.Message)
+
+	defer cancel()
+}
+
+// Testing remove vote takes back the downvote
+func TestRemoveVoteWithPreviousDownvote(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
+	mockVoteFound(models.VoteDown)
+
+	var delta int32
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		delta = crypto.VotesDelta
+		return crypto, 1, nil
+	}
+
+	ctx, cancel := contextWithVoter("voter-test")
+	result, err := server.RemoveVote(ctx, &crypto)
+
+	require.Nil(t, err)
+	require.Equal(t, int32(1), delta)
+	require.Equal(t, "registered remove vote successful", result.Message)
+
+	defer cancel()
+}
+
+// Testing remove vote without previous vote
+func TestRemoveVoteWithoutPreviousVote(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
+	mockVoteFound(models.VoteNone)
+
+	ctx, cancel := contextWithVoter("voter-test")
+	result, err := server.RemoveVote(ctx, &crypto)
+
+	require.Nil(t, err)
+	require.Equal(t, "remove vote already registered", result.Message)
+
+	defer cancel()
+}
+
+// Testing monitor votes with invalid id
+func TestMonitorVotesWithIdInvalid(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToMonitorVotes()
+	crypto.Id = "123abc"
+	mockStream := Mock_EndPointCryptos_MonitorVotesServer{}
+
 	_, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	err := server.MonitorVotes(&cryptoMonitor, &mockStream)
+	err := server.MonitorVotes(&crypto, &mockStream)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = InvalidArgument desc = id is invalid: 123abc err: the provided hex string is not a valid ObjectID", err.Error())
 
 	defer cancel()
+}
+
+// Help function to TestMonitorVotesWithSendError and TestMonitorVotesWithManyStreams,
+// publishes the update as the vote rpcs do
+func mockUpdateToStream(id string) {
+	objId, _ := primitive.ObjectIDFromHex(id)
+	SetObserver(observer.EventVoted, models.CryptoCurrency{Id: objId})
+}
+
+// Help function to run MonitorVotes until it is subscribed in hub
+func startMonitorVotes(t *testing.T, server *AppServer, req *proto.MonitorVotesReq, stream *Mock_EndPointCryptos_MonitorVotesServer) chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- server.MonitorVotes(req, stream)
+	}()
+
+	require.Eventually(t, func() bool {
+		return hub.Subscribers(req.GetId()) > 0
+	}, time.Second*3, time.Millisecond*10)
+
+	return done
+}
+
+// Help function to run MonitorVotesEvents until it is subscribed in hub
+func startMonitorVotesEvents(t *testing.T, server *AppServer, req *proto.MonitorVotesReq, stream *Mock_EndPointCryptos_MonitorVotesEventsServer) chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- server.MonitorVotesEvents(req, stream)
+	}()
+
+	require.Eventually(t, func() bool {
+		return hub.Subscribers(req.GetId()) > 0
+	}, time.Second*3, time.Millisecond*10)
+
+	return done
+}
+
+// Testing monitor votes ends with the error of send
+func TestMonitorVotesWithSendError(t *testing.T) {
+	server := returnMockAppServer()
+
+	cryptoMonitor := returnMockProtoModelToMonitorVotes()
+	mockStream := Mock_EndPointCryptos_MonitorVotesServer{SendErr: errors.New("testing MonitorVotes with error in Send")}
+
+	done := startMonitorVotes(t, &server, &cryptoMonitor, &mockStream)
+	mockUpdateToStream(cryptoMonitor.Id)
+
+	err := <-done
+
+	require.NotNil(t, err)
+	require.Equal(t, "testing MonitorVotes with error in Send", err.Error())
+}
+
+// Testing monitor votes ends with unavailable when streams are stopped by shutdown
+func TestMonitorVotesWithShutdown(t *testing.T) {
+	server := returnMockAppServer()
+	defer StartHub()
+
+	cryptoMonitor := returnMockProtoModelToMonitorVotes()
+	mockStream := Mock_EndPointCryptos_MonitorVotesServer{}
+
+	done := startMonitorVotes(t, &server, &cryptoMonitor, &mockStream)
+	StopStreams()
+	StopStreams()
+
+	err := <-done
 
 	require.NotNil(t, err)
-	require.Equal(t, "testing MonitorVotes with error in GetById", err.Error())
+	require.Equal(t, "rpc error: code = Unavailable desc = server is shutting down", err.Error())
+	require.Empty(t, mockStream.Received())
 }
 
-// Testing monitor votes successful
+// Testing monitor votes successful, it sends the crypto of event without reading it again
 func TestMonitorVotesWithSuccess(t *testing.T) {
-	server := AppServer{}
+	server := returnMockAppServer()
 
 	cryptoResponseStream := returnMockModelCryptoCurrency()
-	cryptoMonitorSendToMonitor := returnMockProtoModelToMonitorVotes()
-	mockStream := Mock_EndPointCryptos_MonitorVotesServer{}
+	cryptoMonitor := returnMockProtoModelToMonitorVotes()
+	objId, _ := primitive.ObjectIDFromHex(cryptoMonitor.Id)
+	cryptoResponseStream.Id = objId
+	cryptoResponseStream.Votes += 1
 
-	StartChanToStream()
-	SetObserver = func(id string) {
-		observer <- cryptoMonitorSendToMonitor.Id
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return models.CryptoCurrency{}, errors.New("crypto of stream is read again")
 	}
 
-	cryptoReceivedUpVote := mockUpdateToStream(server)
-	cryptoMonitorSendToMonitor.Id = cryptoReceivedUpVote.Id
-	objId, _ := primitive.ObjectIDFromHex(cryptoReceivedUpVote.Id)
+	ctx, cancel := context.WithCancel(context.Background())
+	mockStream := Mock_EndPointCryptos_MonitorVotesServer{Ctx: ctx}
+
+	done := startMonitorVotes(t, &server, &cryptoMonitor, &mockStream)
+	SetObserver(observer.EventVoted, cryptoResponseStream)
+
+	require.Eventually(t, func() bool {
+		return len(mockStream.Received()) == 1
+	}, time.Second*3, time.Millisecond*10)
+
+	cancel()
+	require.Nil(t, <-done)
+	require.Equal(t, 0, hub.Subscribers(cryptoMonitor.Id))
+
+	results := mockStream.Received()
+	require.Equal(t, 1, len(results))
+	require.Equal(t, cryptoResponseStream.Id.Hex(), results[0].Id)
+	require.Equal(t, cryptoResponseStream.Name, results[0].Name)
+	require.Equal(t, cryptoResponseStream.Votes, results[0].Votes)
+}
+
+// Testing monitor votes refuses since_sequence, the replay is in MonitorVotesEvents
+func TestMonitorVotesWithSinceSequenceInvalid(t *testing.T) {
+	server := returnMockAppServer()
+	cryptoMonitor := returnMockProtoModelToMonitorVotes()
+	since := int64(1)
+	cryptoMonitor.SinceSequence = &since
+
+	err := server.MonitorVotes(&cryptoMonitor, &Mock_EndPointCryptos_MonitorVotesServer{})
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = InvalidArgument desc = since_sequence is accepted only by MonitorVotesEvents", err.Error())
+}
+
+// Testing two streams of same crypto receive the same update
+func TestMonitorVotesWithManyStreams(t *testing.T) {
+	server := returnMockAppServer()
+
+	cryptoResponseStream := returnMockModelCryptoCurrency()
+	cryptoMonitor := returnMockProtoModelToMonitorVotes()
+	objId, _ := primitive.ObjectIDFromHex(cryptoMonitor.Id)
 	cryptoResponseStream.Id = objId
-	cryptoResponseStream.Votes += 1
 
-	mongodb.GetById = func(coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
 		return cryptoResponseStream, nil
 	}
 
-	// Set timeout to testing finish
-	timeout := time.After(3 * time.Second)
-	go func() {
-		_, cancel := context.WithTimeout(context.Background(), time.Second*5)
-		server.MonitorVotes(&cryptoMonitorSendToMonitor, &mockStream)
-		defer cancel()
-	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	firstStream := Mock_EndPointCryptos_MonitorVotesServer{Ctx: ctx}
+	secondStream := Mock_EndPointCryptos_MonitorVotesServer{Ctx: ctx}
 
-	select {
-	case <-timeout:
-		logger.Info("TESTING", "Timeout successful ")
+	firstDone := startMonitorVotes(t, &server, &cryptoMonitor, &firstStream)
+	secondDone := startMonitorVotes(t, &server, &cryptoMonitor, &secondStream)
+	require.Eventually(t, func() bool {
+		return hub.Subscribers(cryptoMonitor.Id) == 2
+	}, time.Second*3, time.Millisecond*10)
+
+	mockUpdateToStream(cryptoMonitor.Id)
+
+	require.Eventually(t, func() bool {
+		return len(firstStream.Received()) == 1 && len(secondStream.Received()) == 1
+	}, time.Second*3, time.Millisecond*10)
+
+	cancel()
+	require.Nil(t, <-firstDone)
+	require.Nil(t, <-secondDone)
+}
+
+// Testing edit crypto records the new price in history, in the transaction of edit
+func TestEditCryptoRecordsPrice(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToEditCreateCrypto()
+	crypto.PriceUsd = 42.5
+	cryptoId, _ := primitive.ObjectIDFromHex(crypto.Id)
+
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		return crypto, 1, nil
+	}
+
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return returnMockModelCryptoCurrency(), nil
 	}
 
-	require.Equal(t, 1, len(mockStream.Results))
-	require.Equal(t, cryptoResponseStream.Id.Hex(), mockStream.Results[0].Id)
-	require.Equal(t, cryptoResponseStream.Name, mockStream.Results[0].Name)
+	var recordedId primitive.ObjectID
+	var recordedPrice float64
+	defer func(original func(context.Context, mongodb.IMCollection, primitive.ObjectID, float64, time.Time) (bool, error)) {
+		mongodb.RecordPrice = original
+	}(mongodb.RecordPrice)
+	mongodb.RecordPrice = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID, price float64, at time.Time) (bool, error) {
+		recordedId = id
+		recordedPrice = price
+		return false, errors.New("history unavailable")
+	}
+
+	_, err := server.EditCrypto(context.Background(), &crypto)
+
+	// failure in history fails the edit, the transaction is not committed
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = Internal desc = history unavailable", err.Error())
+	require.Equal(t, cryptoId, recordedId)
+	require.Equal(t, 42.5, recordedPrice)
+}
+
+// Testing price history with invalid interval
+func TestGetPriceHistoryWithIntervalInvalid(t *testing.T) {
+	server := returnMockAppServer()
+	req := proto.PriceHistoryReq{Id: primitive.NewObjectID().Hex(), Interval: "5m"}
+
+	result, err := server.GetPriceHistory(context.Background(), &req)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = InvalidArgument desc = interval is invalid, use raw, 1h or 1d: 5m", err.Error())
+	require.Empty(t, result.Buckets)
+}
+
+// Testing price history of crypto not found
+func TestGetPriceHistoryWithGetByIdErrNoDocuments(t *testing.T) {
+	server := returnMockAppServer()
+	req := proto.PriceHistoryReq{Id: primitive.NewObjectID().Hex()}
+
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return returnMockModelCryptoCurrencyEmpty(), mongo.ErrNoDocuments
+	}
+
+	_, err := server.GetPriceHistory(context.Background(), &req)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = NotFound desc = mongo: no documents in result", err.Error())
+}
+
+// Testing price history by day successful
+func TestGetPriceHistoryWithSuccess(t *testing.T) {
+	server := returnMockAppServer()
+	req := proto.PriceHistoryReq{
+		Id:       primitive.NewObjectID().Hex(),
+		From:     "2022-06-01T00:00:00Z",
+		To:       "2022-06-03T00:00:00Z",
+		Interval: "1d",
+	}
+
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return returnMockModelCryptoCurrency(), nil
+	}
+
+	var from, to time.Time
+	var interval time.Duration
+	mongodb.ListPriceBuckets = func(ctx context.Context, coll mongodb.IMCollection, cryptoId primitive.ObjectID, f time.Time, t time.Time, i time.Duration) ([]models.PriceBucket, error) {
+		from, to, interval = f, t, i
+		return []models.PriceBucket{
+			{Start: f, Open: 1, High: 3, Low: 0.5, Close: 2, Count: 4},
+			{Start: f.Add(24 * time.Hour), Open: 2, High: 2, Low: 2, Close: 2, Count: 1},
+		}, nil
+	}
+
+	result, err := server.GetPriceHistory(context.Background(), &req)
+
+	require.Nil(t, err)
+	require.Equal(t, req.Id, result.Id)
+	require.Equal(t, "1d", result.Interval)
+	require.Equal(t, time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC), from.UTC())
+	require.Equal(t, time.Date(2022, 6, 3, 0, 0, 0, 0, time.UTC), to.UTC())
+	require.Equal(t, 24*time.Hour, interval)
+	require.Len(t, result.Buckets, 2)
+	require.Equal(t, "2022-06-01T00:00:00Z", result.Buckets[0].Start)
+	require.Equal(t, 3.0, result.Buckets[0].High)
+	require.Equal(t, 0.5, result.Buckets[0].Low)
+	require.Equal(t, int32(4), result.Buckets[0].Count)
+	require.Equal(t, "2022-06-02T00:00:00Z", result.Buckets[1].Start)
+}
+
+// Testing price history with more prices than the max is out of range
+func TestGetPriceHistoryWithTooManyPrices(t *testing.T) {
+	server := returnMockAppServer()
+	req := proto.PriceHistoryReq{Id: primitive.NewObjectID().Hex()}
+
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return returnMockModelCryptoCurrency(), nil
+	}
+	mongodb.ListPriceBuckets = func(ctx context.Context, coll mongodb.IMCollection, cryptoId primitive.ObjectID, f time.Time, t time.Time, i time.Duration) ([]models.PriceBucket, error) {
+		return nil, mongodb.ErrTooManyPrices
+	}
+
+	_, err := server.GetPriceHistory(context.Background(), &req)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = OutOfRange desc = "+mongodb.ErrTooManyPrices.Error(), err.Error())
+}
+
+// Testing edit crypto writes audit with actor and fields changed
+func TestEditCryptoWritesAuditEvent(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToEditCreateCrypto()
+	crypto.Name = "Created Crypto Test"
+	crypto.AssetId = "TCR"
+	crypto.PriceUsd = 3
+
+	before := returnMockModelCryptoCurrency()
+	before.Id, _ = primitive.ObjectIDFromHex(crypto.Id)
+	after := before
+	after.PriceUsd = 3
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return before, nil
+	}
+
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		return after, 1, nil
+	}
+
+	var audited models.AuditEvent
+	defer func(original func(context.Context, mongodb.IMCollection, models.AuditEvent) error) {
+		mongodb.InsertAuditEvent = original
+	}(mongodb.InsertAuditEvent)
+	mongodb.InsertAuditEvent = func(ctx context.Context, coll mongodb.IMCollection, event models.AuditEvent) error {
+		audited = event
+		return nil
+	}
+
+	ctx := auth.ContextWithIdentity(context.Background(), auth.Identity{Subject: "alice", Roles: []string{auth.RoleAdmin}})
+	_, err := server.EditCrypto(ctx, &crypto)
+
+	require.Nil(t, err)
+	require.Equal(t, "alice", audited.Actor)
+	require.Equal(t, "EditCrypto", audited.RpcMethod)
+	require.Equal(t, before.Id, audited.CryptoId)
+	require.False(t, audited.Time.IsZero())
+	require.Equal(t, []models.AuditChange{{Field: "price_usd", Before: "1.5", After: "3"}}, audited.Changes)
+}
+
+// Testing delete crypto not found before delete
+func TestDeleteCryptoWithGetByIdErrNoDocuments(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToDeleteCrypto()
+
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return returnMockModelCryptoCurrencyEmpty(), mongo.ErrNoDocuments
+	}
+
+	deleted := false
+	mongodb.DeleteById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		deleted = true
+		return models.CryptoCurrency{Id: id}, nil
+	}
+
+	_, err := server.DeleteCrypo(context.Background(), &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = NotFound desc = mongo: no documents in result", err.Error())
+	require.False(t, deleted)
+}
+
+// Testing list audit events with invalid page token
+func TestListAuditEventsWithPageTokenInvalid(t *testing.T) {
+	server := returnMockAppServer()
+	req := proto.ListAuditEventsReq{PageToken: "invalid"}
+
+	mongodb.ListAuditEvents = func(ctx context.Context, coll mongodb.IMCollection, filter repositories.AuditParams, page repositories.PageParams) ([]models.AuditEvent, string, error) {
+		return nil, "", mongodb.ErrInvalidPageToken
+	}
+
+	_, err := server.ListAuditEvents(context.Background(), &req)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = InvalidArgument desc = page_token is invalid", err.Error())
+}
+
+// Testing list audit events by crypto and actor successful
+func TestListAuditEventsWithSuccess(t *testing.T) {
+	server := returnMockAppServer()
+	cryptoId := primitive.NewObjectID()
+	req := proto.ListAuditEventsReq{
+		CryptoId: cryptoId.Hex(),
+		Actor:    "alice",
+		From:     "2022-06-01T00:00:00Z",
+		PageSize: 1,
+	}
+
+	var filter repositories.AuditParams
+	var page repositories.PageParams
+	mongodb.ListAuditEvents = func(ctx context.Context, coll mongodb.IMCollection, f repositories.AuditParams, p repositories.PageParams) ([]models.AuditEvent, string, error) {
+		filter, page = f, p
+		return []models.AuditEvent{{
+			Id:        primitive.NewObjectID(),
+			CryptoId:  cryptoId,
+			Actor:     "alice",
+			RpcMethod: "EditCrypto",
+			Time:      time.Date(2022, 6, 2, 10, 0, 0, 0, time.UTC),
+			Changes:   []models.AuditChange{{Field: "price_usd", Before: "1.5", After: "3"}},
+		}}, "next", nil
+	}
+
+	result, err := server.ListAuditEvents(context.Background(), &req)
+
+	require.Nil(t, err)
+	require.Equal(t, cryptoId, filter.CryptoId)
+	require.Equal(t, "alice", filter.Actor)
+	require.Equal(t, time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC), filter.From)
+	require.True(t, filter.To.IsZero())
+	require.Equal(t, int64(1), page.Size)
+	require.Equal(t, "next", result.NextPageToken)
+	require.Len(t, result.Events, 1)
+	require.Equal(t, "EditCrypto", result.Events[0].RpcMethod)
+	require.Equal(t, "2022-06-02T10:00:00Z", result.Events[0].Time)
+	require.Equal(t, "price_usd", result.Events[0].Changes[0].Field)
+	require.Equal(t, "3", result.Events[0].Changes[0].After)
+}
+
+// Testing restore crypto that is not deleted
+func TestRestoreCryptoWithRestoreByIdErrNoDocuments(t *testing.T) {
+	server := returnMockAppServer()
+	req := proto.RestoreCryptoReq{Id: primitive.NewObjectID().Hex()}
+
+	mongodb.GetDeletedById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return returnMockModelCryptoCurrencyEmpty(), mongo.ErrNoDocuments
+	}
+	restored := false
+	mongodb.RestoreById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		restored = true
+		return returnMockModelCryptoCurrencyEmpty(), mongo.ErrNoDocuments
+	}
+
+	result, err := server.RestoreCrypto(context.Background(), &req)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = NotFound desc = crypto deleted not found: "+req.Id, err.Error())
+	require.Empty(t, result.Id)
+	require.False(t, restored)
+}
+
+// Testing restore crypto when a new crypto uses its asset_id
+func TestRestoreCryptoWithAssetIdUsed(t *testing.T) {
+	server := returnMockAppServer()
+	req := proto.RestoreCryptoReq{Id: primitive.NewObjectID().Hex()}
+
+	mongodb.GetDeletedById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return returnMockModelCryptoCurrency(), nil
+	}
+	mongodb.RestoreById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return returnMockModelCryptoCurrencyEmpty(), returnMockDuplicateKeyError()
+	}
+
+	_, err := server.RestoreCrypto(context.Background(), &req)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = AlreadyExists desc = asset_id of crypto is used by other crypto: "+req.Id, err.Error())
+}
+
+// Testing restore crypto successful, the audit has the deleted_at removed
+func TestRestoreCryptoWithSuccess(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockModelCryptoCurrency()
+	crypto.Votes = 7
+	req := proto.RestoreCryptoReq{Id: crypto.Id.Hex()}
+
+	deleted := crypto
+	deletedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	deleted.DeletedAt = &deletedAt
+	deleted.Deleted = true
+	mongodb.GetDeletedById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return deleted, nil
+	}
+	mongodb.RestoreById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return crypto, nil
+	}
+
+	var audited models.AuditEvent
+	defer func(original func(context.Context, mongodb.IMCollection, models.AuditEvent) error) {
+		mongodb.InsertAuditEvent = original
+	}(mongodb.InsertAuditEvent)
+	mongodb.InsertAuditEvent = func(ctx context.Context, coll mongodb.IMCollection, event models.AuditEvent) error {
+		audited = event
+		return nil
+	}
+
+	result, err := server.RestoreCrypto(context.Background(), &req)
+
+	require.Nil(t, err)
+	require.Equal(t, crypto.Id.Hex(), result.Id)
+	require.Equal(t, crypto.AssetId, result.AssetId)
+	require.Equal(t, int32(7), result.Votes)
+	require.Equal(t, "RestoreCrypto", audited.RpcMethod)
+	require.Equal(t, []models.AuditChange{{Field: "deleted_at", Before: "2024-05-01T12:00:00Z", After: ""}}, audited.Changes)
+}
+
+// Testing purge deleted with invalid retention
+func TestPurgeDeletedWithRetentionInvalid(t *testing.T) {
+	server := returnMockAppServer()
+	req := proto.PurgeDeletedReq{RetentionDays: -1}
+
+	_, err := server.PurgeDeleted(context.Background(), &req)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = InvalidArgument desc = retention_days is invalid: -1", err.Error())
+}
+
+// Testing purge deleted removes the cryptos deleted before the default retention
+func TestPurgeDeletedWithSuccess(t *testing.T) {
+	server := returnMockAppServer()
+	req := proto.PurgeDeletedReq{}
+	deleted := []models.CryptoCurrency{returnMockModelCryptoCurrency(), returnMockModelCryptoCurrency()}
+
+	var before time.Time
+	mongodb.ListDeletedBefore = func(ctx context.Context, coll mongodb.IMCollection, b time.Time) ([]models.CryptoCurrency, error) {
+		before = b
+		return deleted, nil
+	}
+
+	var purgedIds []primitive.ObjectID
+	var related int
+	mongodb.PurgeByIds = func(ctx context.Context, coll mongodb.IMCollection, others []mongodb.IMCollection, ids []primitive.ObjectID) (int64, error) {
+		purgedIds = ids
+		related = len(others)
+		return int64(len(ids)), nil
+	}
+
+	result, err := server.PurgeDeleted(context.Background(), &req)
+
+	require.Nil(t, err)
+	require.Equal(t, int64(2), result.Purged)
+	require.Equal(t, []primitive.ObjectID{deleted[0].Id, deleted[1].Id}, purgedIds)
+	require.Equal(t, 2, related)
+	require.WithinDuration(t, time.Now().AddDate(0, 0, -30), before, time.Minute)
+}
+
+// Testing purge fails when its audit is not saved, the transaction undoes the purge
+func TestPurgeDeletedWithAuditError(t *testing.T) {
+	server := returnMockAppServer()
+	deleted := []models.CryptoCurrency{returnMockModelCryptoCurrency()}
+
+	mongodb.ListDeletedBefore = func(ctx context.Context, coll mongodb.IMCollection, b time.Time) ([]models.CryptoCurrency, error) {
+		return deleted, nil
+	}
+	mongodb.PurgeByIds = func(ctx context.Context, coll mongodb.IMCollection, others []mongodb.IMCollection, ids []primitive.ObjectID) (int64, error) {
+		return int64(len(ids)), nil
+	}
+
+	defer func(original func(context.Context, mongodb.IMCollection, models.AuditEvent) error) {
+		mongodb.InsertAuditEvent = original
+	}(mongodb.InsertAuditEvent)
+	mongodb.InsertAuditEvent = func(ctx context.Context, coll mongodb.IMCollection, event models.AuditEvent) error {
+		return errors.New("some error")
+	}
+
+	var inTransaction bool
+	defer func(original func(context.Context, *mongo.Collection, func(context.Context) error) error) {
+		mongodb.WithTransaction = original
+	}(mongodb.WithTransaction)
+	mongodb.WithTransaction = func(ctx context.Context, coll *mongo.Collection, fn func(ctx context.Context) error) error {
+		inTransaction = true
+		return fn(ctx)
+	}
+
+	_, err := server.PurgeDeleted(context.Background(), &proto.PurgeDeletedReq{})
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = Internal desc = some error", err.Error())
+	require.True(t, inTransaction)
+}
+
+// Testing monitor votes hides the crypto deleted and keeps the stream open
+func TestMonitorVotesWithCryptoDeleted(t *testing.T) {
+	server := returnMockAppServer()
+
+	cryptoMonitor := returnMockProtoModelToMonitorVotes()
+	ctx, cancel := context.WithCancel(context.Background())
+	mockStream := Mock_EndPointCryptos_MonitorVotesServer{Ctx: ctx}
+	objId, _ := primitive.ObjectIDFromHex(cryptoMonitor.Id)
+
+	done := startMonitorVotes(t, &server, &cryptoMonitor, &mockStream)
+	SetObserver(observer.EventDeleted, models.CryptoCurrency{Id: objId})
+
+	select {
+	case err := <-done:
+		t.Fatalf("stream ended with crypto deleted: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel()
+	require.Nil(t, <-done)
+	require.Empty(t, mockStream.Received())
+}
+
+// Testing edit crypto with expected version different of the current one
+func TestEditCryptoWithVersionMismatch(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToEditCreateCrypto()
+	expected := int64(2)
+	crypto.ExpectedVersion = &expected
+
+	current := returnMockModelCryptoCurrency()
+	current.Version = 3
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return current, nil
+	}
+
+	updated := false
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		updated = true
+		return crypto, 1, nil
+	}
+
+	_, err := server.EditCrypto(context.Background(), &crypto)
+
+	require.NotNil(t, err)
+	require.False(t, updated)
+	errStatus := status.Convert(err)
+	require.Equal(t, "rpc error: code = Aborted desc = version is 3, expected 2", err.Error())
+	require.Len(t, errStatus.Details(), 1)
+	detail, ok := errStatus.Details()[0].(*proto.CryptoCurrency)
+	require.True(t, ok)
+	require.Equal(t, current.Id.Hex(), detail.Id)
+	require.Equal(t, int64(3), detail.Version)
+}
+
+// Testing edit crypto updated by other request between the read and the update
+func TestEditCryptoWithVersionChangedConcurrently(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToEditCreateCrypto()
+	expected := int64(4)
+	crypto.ExpectedVersion = &expected
+
+	calls := 0
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		calls++
+		current := returnMockModelCryptoCurrency()
+		current.Version = 4
+		if calls > 1 {
+			current.Version = 5
+		}
+		return current, nil
+	}
+
+	var filtered *int64
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		filtered = crypto.ExpectedVersion
+		return crypto, 0, nil
+	}
+
+	_, err := server.EditCrypto(context.Background(), &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, int64(4), *filtered)
+	require.Equal(t, "rpc error: code = Aborted desc = version is 5, expected 4", err.Error())
+	detail := status.Convert(err).Details()[0].(*proto.CryptoCurrency)
+	require.Equal(t, int64(5), detail.Version)
+}
+
+// Testing upvote with commit of transaction failed, the vote is not registered
+func TestUpvoteWithTransactionError(t *testing.T) {
+	server := returnMockAppServer()
+	crypto := returnMockProtoModelToVote()
+	mockVoteFound(models.VoteNone)
+
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		return crypto, 1, nil
+	}
+
+	defer func(original func(context.Context, *mongo.Collection, func(context.Context) error) error) {
+		mongodb.WithTransaction = original
+	}(mongodb.WithTransaction)
+	mongodb.WithTransaction = func(ctx context.Context, coll *mongo.Collection, fn func(ctx context.Context) error) error {
+		err := fn(ctx)
+		if err != nil {
+			return err
+		}
+		return errors.New("commit failed")
+	}
+
+	ctx, cancel := contextWithVoter("voter-test")
+	defer cancel()
+	_, err := server.Upvote(ctx, &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = Internal desc = commit failed", err.Error())
+}
+
+// Transaction that keeps the writes only when the callback succeeds, as the commit and the abort of database
+type mockTransaction struct {
+	pending   []string
+	committed []string
+	err       error
+}
+
+func (m *mockTransaction) write(name string) {
+	m.pending = append(m.pending, name)
+}
+
+// Help function to run the writes in a mockTransaction and to fail the audit, restored at the end of test
+func useMockTransactionWithAuditError(t *testing.T) *mockTransaction {
+	transaction := &mockTransaction{}
+	withTransaction, insertAuditEvent := mongodb.WithTransaction, mongodb.InsertAuditEvent
+	t.Cleanup(func() {
+		mongodb.WithTransaction = withTransaction
+		mongodb.InsertAuditEvent = insertAuditEvent
+	})
+
+	mongodb.WithTransaction = func(ctx context.Context, coll *mongo.Collection, fn func(ctx context.Context) error) error {
+		transaction.pending = nil
+		transaction.err = fn(ctx)
+		if transaction.err == nil {
+			transaction.committed = append(transaction.committed, transaction.pending...)
+		}
+		return transaction.err
+	}
+	mongodb.InsertAuditEvent = func(ctx context.Context, coll mongodb.IMCollection, event models.AuditEvent) error {
+		return errors.New("audit unavailable")
+	}
+	return transaction
+}
+
+// Help function to require no event of crypto is published in a while
+func requireNoEvent(t *testing.T, subscription *observer.Subscription) {
+	select {
+	case event := <-subscription.Events():
+		t.Fatalf("event published of write not committed: %v", event.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// Testing upvote with audit error rolls back the vote and the votes of crypto, without cache and event
+func TestUpvoteWithAuditErrorRollsBack(t *testing.T) {
+	memory := cache.NewMemory(10, cache.TTL{})
+	server := AppServer{Cache: memory}
+	crypto := returnMockProtoModelToVote()
+	transaction := useMockTransactionWithAuditError(t)
+
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return models.CryptoCurrency{Id: id, Votes: 1}, nil
+	}
+	mongodb.SetVote = func(ctx context.Context, coll mongodb.IMCollection, voterId string, cryptoId primitive.ObjectID, value int32) (int32, error) {
+		transaction.write("vote")
+		return models.VoteNone, nil
+	}
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		transaction.write("crypto")
+		return crypto, 1, nil
+	}
+
+	subscription := hub.Subscribe(crypto.Id)
+	defer subscription.Unsubscribe()
+
+	ctx, cancel := contextWithVoter("voter-test")
+	defer cancel()
+	_, err := server.Upvote(ctx, &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = Internal desc = audit unavailable", err.Error())
+	// the error of audit is returned by the callback, so the transaction aborts
+	require.Equal(t, "audit unavailable", transaction.err.Error())
+	require.Equal(t, []string{"vote", "crypto"}, transaction.pending)
+	require.Empty(t, transaction.committed)
+	require.Nil(t, memory.Get(ctx, crypto.Id))
+	requireNoEvent(t, subscription)
+}
+
+// Testing edit with audit error rolls back the crypto and the price, without cache and event
+func TestEditCryptoWithAuditErrorRollsBack(t *testing.T) {
+	memory := cache.NewMemory(10, cache.TTL{})
+	server := AppServer{Cache: memory}
+	crypto := returnMockProtoModelToEditCreateCrypto()
+	crypto.PriceUsd = 42.5
+	transaction := useMockTransactionWithAuditError(t)
+
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return returnMockModelCryptoCurrency(), nil
+	}
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		transaction.write("crypto")
+		return crypto, 1, nil
+	}
+
+	defer func(original func(context.Context, mongodb.IMCollection, primitive.ObjectID, float64, time.Time) (bool, error)) {
+		mongodb.RecordPrice = original
+	}(mongodb.RecordPrice)
+	mongodb.RecordPrice = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID, price float64, at time.Time) (bool, error) {
+		transaction.write("price")
+		return true, nil
+	}
+
+	subscription := hub.Subscribe(crypto.Id)
+	defer subscription.Unsubscribe()
+
+	_, err := server.EditCrypto(context.Background(), &crypto)
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = Internal desc = audit unavailable", err.Error())
+	require.Equal(t, "audit unavailable", transaction.err.Error())
+	require.Equal(t, []string{"crypto", "price"}, transaction.pending)
+	require.Empty(t, transaction.committed)
+	require.Nil(t, memory.Get(context.Background(), crypto.Id))
+	requireNoEvent(t, subscription)
+}
+
+// Testing a change of change stream deletes the cache and notifies the streams, the writes do not publish again
+func TestOnCryptoChangeWithSuccess(t *testing.T) {
+	memory := cache.NewMemory(10, cache.TTL{})
+	server := AppServer{Cache: memory}
+
+	UseWatcher(true)
+	defer UseWatcher(false)
+
+	cryptoResponseStream := returnMockModelCryptoCurrency()
+	cryptoMonitor := returnMockProtoModelToMonitorVotes()
+	objId, _ := primitive.ObjectIDFromHex(cryptoMonitor.Id)
+	cryptoResponseStream.Id = objId
+
+	mongodb.GetById = func(ctx context.Context, coll mongodb.IMCollection, id primitive.ObjectID) (models.CryptoCurrency, error) {
+		return cryptoResponseStream, nil
+	}
+
+	listKey := cache.PrefixDeleteAll + "-name-true"
+	require.Nil(t, memory.Set(context.Background(), cryptoMonitor.Id, cryptoResponseStream, cache.NoDeleteAll))
+	require.Nil(t, memory.SetByByte(context.Background(), listKey, "[]", cache.NoDeleteAll))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockStream := Mock_EndPointCryptos_MonitorVotesServer{Ctx: ctx}
+	done := startMonitorVotes(t, &server, &cryptoMonitor, &mockStream)
+
+	// write of this replica, published only by the change stream
+	SetObserver(observer.EventUpdated, cryptoResponseStream)
+
+	change := mongodb.CryptoChange{OperationType: "update"}
+	change.DocumentKey.Id = objId
+	server.OnCryptoChange(context.Background(), change)
+
+	require.Eventually(t, func() bool {
+		return len(mockStream.Received()) == 1
+	}, time.Second*3, time.Millisecond*10)
+
+	cancel()
+	require.Nil(t, <-done)
+	require.Equal(t, 1, len(mockStream.Received()))
+
+	require.Nil(t, memory.Get(context.Background(), cryptoMonitor.Id))
+	require.Nil(t, memory.Get(context.Background(), listKey))
+}
+
+type Mock_EndPointCryptos_WatchCryptosServer struct {
+	grpc.ServerStream
+	Ctx     context.Context
+	mu      sync.Mutex
+	Results []*proto.CryptoEvent
+	Block   chan struct{} // Send waits until it is closed
+}
+
+// MonitorVotesEvents sends the same events
+type Mock_EndPointCryptos_MonitorVotesEventsServer = Mock_EndPointCryptos_WatchCryptosServer
+
+func (mock *Mock_EndPointCryptos_WatchCryptosServer) Send(event *proto.CryptoEvent) error {
+	if mock.Block != nil {
+		<-mock.Block
+	}
+
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.Results = append(mock.Results, event)
+	return nil
+}
+
+func (mock *Mock_EndPointCryptos_WatchCryptosServer) Context() context.Context {
+	return mock.Ctx
+}
+
+func (mock *Mock_EndPointCryptos_WatchCryptosServer) Received() []*proto.CryptoEvent {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	return append([]*proto.CryptoEvent{}, mock.Results...)
+}
+
+// Testing watch with ids and all together is invalid
+func TestWatchCryptosWithIdsAndAll(t *testing.T) {
+	server := returnMockAppServer()
+	req := proto.WatchCryptosReq{Ids: []string{primitive.NewObjectID().Hex()}, All: true}
+
+	err := server.WatchCryptos(&req, &Mock_EndPointCryptos_WatchCryptosServer{Ctx: context.Background()})
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = InvalidArgument desc = ids must be empty when all is true", err.Error())
+}
+
+// Testing watch of all receives the crypto created and its votes, in order of sequence
+func TestWatchCryptosAllWithSuccess(t *testing.T) {
+	server := returnMockAppServer()
+	created := returnMockModelCryptoCurrency()
+	created.Id = primitive.NewObjectID()
+
+	mongodb.InsertCryptos = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, error) {
+		return created, nil
+	}
+	mockVoteFound(models.VoteNone)
+	mongodb.UpdateCrypto = func(ctx context.Context, coll mongodb.IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
+		voted := created
+		voted.Votes = 1
+		return voted, 1, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockStream := Mock_EndPointCryptos_WatchCryptosServer{Ctx: ctx}
+	done := make(chan error, 1)
+	go func() {
+		done <- server.WatchCryptos(&proto.WatchCryptosReq{All: true}, &mockStream)
+	}()
+	require.Eventually(t, func() bool {
+		return hub.Subscribers(observer.All) > 0
+	}, time.Second*3, time.Millisecond*10)
+
+	createReq := returnMockProtoModelCreateCrypto()
+	_, err := server.CreateCrypto(context.Background(), &createReq)
+	require.Nil(t, err)
+
+	voteCtx, cancelVote := contextWithVoter("voter-test")
+	defer cancelVote()
+	_, err = server.Upvote(voteCtx, &proto.VoteReq{Id: created.Id.Hex()})
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(mockStream.Received()) == 2
+	}, time.Second*3, time.Millisecond*10)
+
+	cancel()
+	require.Nil(t, <-done)
+	require.Equal(t, 0, hub.Subscribers(observer.All))
+
+	events := mockStream.Received()
+	require.Equal(t, proto.CryptoEventType_CREATED, events[0].Type)
+	require.Equal(t, created.Id.Hex(), events[0].Crypto.Id)
+	require.Equal(t, proto.CryptoEventType_VOTED, events[1].Type)
+	require.Equal(t, int32(1), events[1].Crypto.Votes)
+	require.Greater(t, events[1].Sequence, events[0].Sequence)
+}
+
+// Testing watch of ids does not receive events of other cryptos
+func TestWatchCryptosWithIds(t *testing.T) {
+	server := returnMockAppServer()
+	watched := primitive.NewObjectID()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockStream := Mock_EndPointCryptos_WatchCryptosServer{Ctx: ctx}
+	done := make(chan error, 1)
+	go func() {
+		done <- server.WatchCryptos(&proto.WatchCryptosReq{Ids: []string{watched.Hex()}}, &mockStream)
+	}()
+	require.Eventually(t, func() bool {
+		return hub.Subscribers(watched.Hex()) > 0
+	}, time.Second*3, time.Millisecond*10)
+
+	SetObserver(observer.EventUpdated, models.CryptoCurrency{Id: primitive.NewObjectID()})
+	SetObserver(observer.EventDeleted, models.CryptoCurrency{Id: watched})
+
+	require.Eventually(t, func() bool {
+		return len(mockStream.Received()) == 1
+	}, time.Second*3, time.Millisecond*10)
+
+	cancel()
+	require.Nil(t, <-done)
+
+	events := mockStream.Received()
+	require.Equal(t, 1, len(events))
+	require.Equal(t, proto.CryptoEventType_DELETED, events[0].Type)
+	require.Equal(t, watched.Hex(), events[0].Crypto.Id)
+}
+
+// Testing type of event of each change of change stream
+func TestEventOfChange(t *testing.T) {
+	id := primitive.NewObjectID()
+	change := func(operation string, updated bson.M) mongodb.CryptoChange {
+		change := mongodb.CryptoChange{OperationType: operation}
+		change.DocumentKey.Id = id
+		change.UpdateDescription.UpdatedFields = updated
+		return change
+	}
+
+	cases := map[string]mongodb.CryptoChange{
+		observer.EventCreated: change("insert", nil),
+		observer.EventVoted:   change("update", bson.M{"votes": 2, "updated_at": time.Now(), "version": 3}),
+		observer.EventUpdated: change("update", bson.M{"votes": 2, "price_usd": 1.5}),
+		observer.EventDeleted: change("update", bson.M{"deleted_at": time.Now()}),
+	}
+	for expected, change := range cases {
+		event := eventOfChange(change)
+		require.Equal(t, expected, event.Type)
+		require.Equal(t, id.Hex(), event.Id)
+		require.Equal(t, id, event.Crypto.Id)
+	}
+
+	restore := change("update", bson.M{"updated_at": time.Now()})
+	restore.UpdateDescription.RemovedFields = []string{"deleted_at"}
+	require.Equal(t, observer.EventUpdated, eventOfChange(restore).Type)
+	require.Equal(t, observer.EventDeleted, eventOfChange(change("delete", nil)).Type)
+}
+
+// Help function to use a backplane with log of size in the test, returns the sequence before the first event
+func useLocalBackplane(t *testing.T, size int) int64 {
+	previous := backplane
+	local := observer.NewLocal(hub, size)
+	SetBackplane(local)
+	t.Cleanup(func() { SetBackplane(previous) })
+	return local.Sequence()
+}
+
+// Testing watch with negative since_sequence is invalid
+func TestWatchCryptosWithSinceSequenceInvalid(t *testing.T) {
+	server := returnMockAppServer()
+	since := int64(-1)
+	req := proto.WatchCryptosReq{All: true, SinceSequence: &since}
+
+	err := server.WatchCryptos(&req, &Mock_EndPointCryptos_WatchCryptosServer{Ctx: context.Background()})
+
+	require.NotNil(t, err)
+	require.Equal(t, "rpc error: code = InvalidArgument desc = since_sequence is invalid: -1", err.Error())
+}
+
+// Testing watch from since_sequence receives GAP, the events kept in log and then the live ones
+func TestWatchCryptosWithSinceSequence(t *testing.T) {
+	base := useLocalBackplane(t, 3)
+	server := returnMockAppServer()
+	watched := models.CryptoCurrency{Id: primitive.NewObjectID()}
+
+	SetObserver(observer.EventCreated, watched)                                            // 1, evicted
+	SetObserver(observer.EventCreated, models.CryptoCurrency{Id: primitive.NewObjectID()}) // 2, evicted
+	SetObserver(observer.EventVoted, watched)                                              // 3
+	SetObserver(observer.EventCreated, models.CryptoCurrency{Id: primitive.NewObjectID()}) // 4, other crypto
+	SetObserver(observer.EventVoted, watched)                                              // 5
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockStream := Mock_EndPointCryptos_WatchCryptosServer{Ctx: ctx}
+	since := int64(0)
+	done := make(chan error, 1)
+	go func() {
+		done <- server.WatchCryptos(&proto.WatchCryptosReq{Ids: []string{watched.Id.Hex()}, SinceSequence: &since}, &mockStream)
+	}()
+	require.Eventually(t, func() bool {
+		return len(mockStream.Received()) == 3
+	}, time.Second*3, time.Millisecond*10)
+
+	SetObserver(observer.EventDeleted, watched) // 6
+
+	require.Eventually(t, func() bool {
+		return len(mockStream.Received()) == 4
+	}, time.Second*3, time.Millisecond*10)
+
+	cancel()
+	require.Nil(t, <-done)
+
+	events := mockStream.Received()
+	require.Equal(t, proto.CryptoEventType_GAP, events[0].Type)
+	require.Equal(t, int64(0), events[0].Sequence)
+	require.Equal(t, base+3, events[1].Sequence)
+	require.Equal(t, proto.CryptoEventType_VOTED, events[1].Type)
+	require.Equal(t, watched.Id.Hex(), events[1].Crypto.Id)
+	require.Equal(t, base+5, events[2].Sequence)
+	require.Equal(t, base+6, events[3].Sequence)
+	require.Equal(t, proto.CryptoEventType_DELETED, events[3].Type)
+}
+
+// Testing monitor events from since_sequence receives the cryptos of log after it, without gap
+func TestMonitorVotesEventsWithSinceSequence(t *testing.T) {
+	base := useLocalBackplane(t, 3)
+	server := returnMockAppServer()
+	crypto := returnMockModelCryptoCurrency()
+	crypto.Id = primitive.NewObjectID()
+
+	crypto.Votes = 1
+	SetObserver(observer.EventVoted, crypto) // 1
+	crypto.Votes = 2
+	SetObserver(observer.EventVoted, crypto) // 2
+	crypto.Votes = 3
+	SetObserver(observer.EventVoted, crypto) // 3
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockStream := Mock_EndPointCryptos_MonitorVotesEventsServer{Ctx: ctx}
+	since := base + 1
+	done := startMonitorVotesEvents(t, &server, &proto.MonitorVotesReq{Id: crypto.Id.Hex(), SinceSequence: &since}, &mockStream)
+
+	require.Eventually(t, func() bool {
+		return len(mockStream.Received()) == 2
+	}, time.Second*3, time.Millisecond*10)
+
+	cancel()
+	require.Nil(t, <-done)
+
+	results := mockStream.Received()
+	require.Equal(t, base+2, results[0].Sequence)
+	require.Equal(t, int32(2), results[0].Crypto.Votes)
+	require.Equal(t, base+3, results[1].Sequence)
+	require.Equal(t, int32(3), results[1].Crypto.Votes)
+}
+
+// Testing monitor events from a sequence evicted of log sends GAP before the events kept
+func TestMonitorVotesEventsWithSinceSequenceEvicted(t *testing.T) {
+	base := useLocalBackplane(t, 3)
+	server := returnMockAppServer()
+	crypto := returnMockModelCryptoCurrency()
+	crypto.Id = primitive.NewObjectID()
+
+	for i := 0; i < 4; i++ {
+		SetObserver(observer.EventVoted, crypto)
+	}
+	SetObserver(observer.EventDeleted, crypto) // 5, not sent
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockStream := Mock_EndPointCryptos_MonitorVotesEventsServer{Ctx: ctx}
+	since := int64(0)
+	done := startMonitorVotesEvents(t, &server, &proto.MonitorVotesReq{Id: crypto.Id.Hex(), SinceSequence: &since}, &mockStream)
+
+	require.Eventually(t, func() bool {
+		return len(mockStream.Received()) == 3
+	}, time.Second*3, time.Millisecond*10)
+
+	cancel()
+	require.Nil(t, <-done)
+
+	results := mockStream.Received()
+	require.Equal(t, proto.CryptoEventType_GAP, results[0].Type)
+	require.Equal(t, int64(0), results[0].Sequence)
+	require.Equal(t, base+3, results[1].Sequence)
+	require.Equal(t, base+4, results[2].Sequence)
+}
+
+// Testing monitor events keeps the live events published while the replay is sent, more than the queue of stream
+func TestMonitorVotesEventsWithSinceSequenceAndLiveEvents(t *testing.T) {
+	base := useLocalBackplane(t, 100)
+
+	server := returnMockAppServer()
+	crypto := returnMockModelCryptoCurrency()
+	crypto.Id = primitive.NewObjectID()
+	SetObserver(observer.EventVoted, crypto) // 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockStream := Mock_EndPointCryptos_MonitorVotesEventsServer{Ctx: ctx, Block: make(chan struct{})}
+	since := int64(0)
+	done := startMonitorVotesEvents(t, &server, &proto.MonitorVotesReq{Id: crypto.Id.Hex(), SinceSequence: &since}, &mockStream)
+
+	live := observer.DefaultBufferSize * 2
+	for i := 0; i < live; i++ {
+		SetObserver(observer.EventVoted, crypto)
+	}
+	close(mockStream.Block)
+
+	require.Eventually(t, func() bool {
+		return len(mockStream.Received()) == live+1
+	}, time.Second*3, time.Millisecond*10)
+
+	cancel()
+	require.Nil(t, <-done)
+
+	for i, event := range mockStream.Received() {
+		require.Equal(t, base+int64(i+1), event.Sequence)
+	}
 }
@@ -0,0 +1,209 @@
+package healthcheck
+
+import (
+	"api-desafio-kvr/helpers"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+var logger = &helpers.Log{}
+var nameLog = "HEALTH"
+
+// Probe returns error when the dependency is unreachable
+type Probe func(ctx context.Context) error
+
+type Config struct {
+	Interval helpers.Duration `json:"interval"` // time between probes
+	Timeout  helpers.Duration `json:"timeout"`  // max time of one probe
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Interval: helpers.Duration{Duration: 10 * time.Second},
+		Timeout:  helpers.Duration{Duration: 2 * time.Second},
+	}
+}
+
+// LoadConfig reads section "health" of CONFIG_FILE and the env vars HEALTH_*
+func LoadConfig() (Config, error) {
+	cfg := DefaultConfig()
+
+	err := helpers.LoadConfigFile("health", &cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	envs := map[string]interface{}{
+		"HEALTH_INTERVAL": &cfg.Interval,
+		"HEALTH_TIMEOUT":  &cfg.Timeout,
+	}
+	for key, target := range envs {
+		err = helpers.SetFromEnv(key, target)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	// the ticker panics with interval 0 and every probe fails with timeout 0
+	if cfg.Interval.Duration <= 0 {
+		return cfg, errors.New("health interval is invalid: " + cfg.Interval.String())
+	}
+	if cfg.Timeout.Duration <= 0 {
+		return cfg, errors.New("health timeout is invalid: " + cfg.Timeout.String())
+	}
+
+	return cfg, nil
+}
+
+// Checker keeps the status of grpc.health.v1: each dependency has its own service name
+// and the services of application are SERVING only after SetReady and while all dependencies are up.
+type Checker struct {
+	server   *health.Server
+	config   Config
+	services []string
+
+	mu      sync.Mutex
+	ready   bool
+	running bool
+	probes  map[string]Probe
+	up      map[string]bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New starts with all services NOT_SERVING, "" is the status of whole server
+func New(cfg Config, services ...string) *Checker {
+	c := &Checker{
+		server:   health.NewServer(),
+		config:   cfg,
+		services: append([]string{""}, services...),
+		probes:   map[string]Probe{},
+		up:       map[string]bool{},
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	for _, service := range c.services {
+		c.server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return c
+}
+
+// Server is registered in grpc server
+func (c *Checker) Server() *health.Server {
+	return c.server
+}
+
+// Add registers the probe of one dependency, it starts NOT_SERVING until it is checked
+func (c *Checker) Add(name string, probe Probe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.probes[name] = probe
+	c.up[name] = false
+	c.server.SetServingStatus(name, healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+// SetReady runs the probes and releases the services of application, called after migration
+func (c *Checker) SetReady() {
+	c.mu.Lock()
+	c.ready = true
+	c.mu.Unlock()
+
+	c.Check()
+}
+
+// Check runs all probes once and updates the status
+func (c *Checker) Check() {
+	c.mu.Lock()
+	probes := make(map[string]Probe, len(c.probes))
+	for name, probe := range c.probes {
+		probes[name] = probe
+	}
+	c.mu.Unlock()
+
+	results := make(map[string]bool, len(probes))
+	for name, probe := range probes {
+		ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout.Duration)
+		err := probe(ctx)
+		cancel()
+
+		if err != nil {
+			logger.Warn(nameLog, "Dependency "+name+" is unreachable: "+err.Error())
+		}
+		results[name] = err == nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	serving := c.ready
+	for name, up := range results {
+		if up != c.up[name] && up {
+			logger.Info(nameLog, "Dependency "+name+" is reachable")
+		}
+		c.up[name] = up
+		c.server.SetServingStatus(name, servingStatus(up))
+		serving = serving && up
+	}
+
+	for _, service := range c.services {
+		c.server.SetServingStatus(service, servingStatus(serving))
+	}
+}
+
+// Start runs the probes in background by the interval
+func (c *Checker) Start() {
+	c.mu.Lock()
+	c.running = true
+	c.mu.Unlock()
+
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(c.config.Interval.Duration)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				c.Check()
+			}
+		}
+	}()
+}
+
+// Shutdown stops the probes and sets all services NOT_SERVING, so new traffic goes to other instances
+func (c *Checker) Shutdown() {
+	select {
+	case <-c.stop:
+		return
+	default:
+		close(c.stop)
+	}
+
+	c.mu.Lock()
+	running := c.running
+	c.ready = false
+	c.mu.Unlock()
+
+	if running {
+		<-c.done
+	}
+	c.server.Shutdown()
+}
+
+func servingStatus(up bool) healthpb.HealthCheckResponse_ServingStatus {
+	if up {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
@@ -0,0 +1,114 @@
+package healthcheck
+
+import (
+	"api-desafio-kvr/helpers"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func statusOf(t *testing.T, c *Checker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	resp, err := c.Server().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.Nil(t, err)
+	return resp.Status
+}
+
+func probeWith(up *int32) Probe {
+	return func(ctx context.Context) error {
+		if atomic.LoadInt32(up) == 1 {
+			return nil
+		}
+		return errors.New("connection refused")
+	}
+}
+
+// Testing services are NOT_SERVING until ready even with dependencies up
+func TestNotServingUntilReady(t *testing.T) {
+	up := int32(1)
+	checker := New(DefaultConfig(), "proto.EndPointCryptos")
+	checker.Add("mongodb", probeWith(&up))
+
+	checker.Check()
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, statusOf(t, checker, "mongodb"))
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, statusOf(t, checker, ""))
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, statusOf(t, checker, "proto.EndPointCryptos"))
+
+	checker.SetReady()
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, statusOf(t, checker, ""))
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, statusOf(t, checker, "proto.EndPointCryptos"))
+}
+
+// Testing background probe flips the status when a dependency goes down and back
+func TestProbeFlipsStatus(t *testing.T) {
+	mongoUp, redisUp := int32(1), int32(1)
+	checker := New(Config{
+		Interval: durationOf(5 * time.Millisecond),
+		Timeout:  durationOf(time.Second),
+	})
+	checker.Add("mongodb", probeWith(&mongoUp))
+	checker.Add("redis", probeWith(&redisUp))
+	checker.SetReady()
+	checker.Start()
+	defer checker.Shutdown()
+
+	atomic.StoreInt32(&redisUp, 0)
+	require.Eventually(t, func() bool {
+		return statusOf(t, checker, "") == healthpb.HealthCheckResponse_NOT_SERVING
+	}, time.Second, time.Millisecond*5)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, statusOf(t, checker, "redis"))
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, statusOf(t, checker, "mongodb"))
+
+	atomic.StoreInt32(&redisUp, 1)
+	require.Eventually(t, func() bool {
+		return statusOf(t, checker, "") == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, time.Millisecond*5)
+}
+
+// Testing shutdown sets all services NOT_SERVING
+func TestShutdown(t *testing.T) {
+	up := int32(1)
+	checker := New(DefaultConfig())
+	checker.Add("mongodb", probeWith(&up))
+	checker.SetReady()
+	checker.Start()
+
+	checker.Shutdown()
+	checker.Shutdown()
+
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, statusOf(t, checker, ""))
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, statusOf(t, checker, "mongodb"))
+}
+
+func durationOf(d time.Duration) helpers.Duration {
+	return helpers.Duration{Duration: d}
+}
+
+// Testing interval and timeout must be greater than zero
+func TestLoadConfigWithDurationNotPositive(t *testing.T) {
+	t.Setenv(helpers.ConfigFileEnv, "")
+
+	cfg, err := LoadConfig()
+	require.Nil(t, err)
+	require.Equal(t, DefaultConfig(), cfg)
+
+	t.Setenv("HEALTH_INTERVAL", "0s")
+	_, err = LoadConfig()
+	require.NotNil(t, err)
+	require.Equal(t, "health interval is invalid: 0s", err.Error())
+
+	t.Setenv("HEALTH_INTERVAL", "-5s")
+	_, err = LoadConfig()
+	require.NotNil(t, err)
+	require.Equal(t, "health interval is invalid: -5s", err.Error())
+
+	t.Setenv("HEALTH_INTERVAL", "5s")
+	t.Setenv("HEALTH_TIMEOUT", "0s")
+	_, err = LoadConfig()
+	require.NotNil(t, err)
+	require.Equal(t, "health timeout is invalid: 0s", err.Error())
+}
@@ -0,0 +1,98 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+)
+
+// The configs are loaded in this order, each one overrides the previous:
+// default values, section of json file in CONFIG_FILE and env vars.
+
+const ConfigFileEnv = "CONFIG_FILE"
+
+// Duration accepts "10s", "1m30s" in json file
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var value string
+	err := json.Unmarshal(b, &value)
+	if err != nil {
+		return errors.New("duration is invalid: " + string(b))
+	}
+
+	d.Duration, err = time.ParseDuration(value)
+	if err != nil {
+		return errors.New("duration is invalid: " + value)
+	}
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// LoadConfigFile decodes the section of json file in CONFIG_FILE into cfg, without file nothing is changed
+func LoadConfigFile(section string, cfg interface{}) error {
+	path := os.Getenv(ConfigFileEnv)
+	if path == "" {
+		return nil
+	}
+
+	byteFile, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	sections := map[string]json.RawMessage{}
+	err = json.Unmarshal(byteFile, &sections)
+	if err != nil {
+		return errors.New("config file " + path + " is invalid: " + err.Error())
+	}
+
+	value, ok := sections[section]
+	if !ok {
+		return nil
+	}
+
+	err = json.Unmarshal(value, cfg)
+	if err != nil {
+		return errors.New("section " + section + " of config file is invalid: " + err.Error())
+	}
+	return nil
+}
+
+// SetFromEnv overrides target with the env var key, empty env var does not change target
+func SetFromEnv(key string, target interface{}) error {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return nil
+	}
+
+	var err error
+	switch t := target.(type) {
+	case *string:
+		*t = value
+	case *int:
+		*t, err = strconv.Atoi(value)
+	case *uint64:
+		*t, err = strconv.ParseUint(value, 10, 64)
+	case *float64:
+		*t, err = strconv.ParseFloat(value, 64)
+	case *bool:
+		*t, err = strconv.ParseBool(value)
+	case *Duration:
+		t.Duration, err = time.ParseDuration(value)
+	default:
+		return errors.New("type of env " + key + " is not supported")
+	}
+
+	if err != nil {
+		return errors.New("env " + key + " is invalid: " + value)
+	}
+	return nil
+}
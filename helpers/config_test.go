@@ -0,0 +1,73 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type configTest struct {
+	Name    string   `json:"name"`
+	Size    int      `json:"size"`
+	Timeout Duration `json:"timeout"`
+}
+
+func TestLoadConfigFileWithoutFile(t *testing.T) {
+	t.Setenv(ConfigFileEnv, "")
+	cfg := configTest{Name: "default"}
+
+	err := LoadConfigFile("test", &cfg)
+	require.Nil(t, err)
+	require.Equal(t, "default", cfg.Name)
+}
+
+func TestLoadConfigFileWithSection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(path, []byte(`{"test": {"name": "file", "timeout": "5s"}, "other": {"name": "other"}}`), 0600)
+	require.Nil(t, err)
+	t.Setenv(ConfigFileEnv, path)
+	cfg := configTest{Name: "default", Size: 10}
+
+	err = LoadConfigFile("test", &cfg)
+	require.Nil(t, err)
+	require.Equal(t, "file", cfg.Name)
+	require.Equal(t, 10, cfg.Size)
+	require.Equal(t, 5*time.Second, cfg.Timeout.Duration)
+}
+
+func TestLoadConfigFileWithDurationInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(path, []byte(`{"test": {"timeout": "5 seconds"}}`), 0600)
+	require.Nil(t, err)
+	t.Setenv(ConfigFileEnv, path)
+	cfg := configTest{}
+
+	err = LoadConfigFile("test", &cfg)
+	require.NotNil(t, err)
+	require.Equal(t, "section test of config file is invalid: duration is invalid: 5 seconds", err.Error())
+}
+
+func TestSetFromEnvWithSuccess(t *testing.T) {
+	t.Setenv("TEST_CONFIG_SIZE", "20")
+	t.Setenv("TEST_CONFIG_TIMEOUT", "1m")
+	cfg := configTest{Name: "default"}
+
+	require.Nil(t, SetFromEnv("TEST_CONFIG_NAME", &cfg.Name))
+	require.Nil(t, SetFromEnv("TEST_CONFIG_SIZE", &cfg.Size))
+	require.Nil(t, SetFromEnv("TEST_CONFIG_TIMEOUT", &cfg.Timeout))
+	require.Equal(t, "default", cfg.Name)
+	require.Equal(t, 20, cfg.Size)
+	require.Equal(t, time.Minute, cfg.Timeout.Duration)
+}
+
+func TestSetFromEnvWithInvalid(t *testing.T) {
+	t.Setenv("TEST_CONFIG_SIZE", "abc")
+	cfg := configTest{}
+
+	err := SetFromEnv("TEST_CONFIG_SIZE", &cfg.Size)
+	require.NotNil(t, err)
+	require.Equal(t, "env TEST_CONFIG_SIZE is invalid: abc", err.Error())
+}
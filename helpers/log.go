@@ -1,65 +1,252 @@
 package helpers
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"os"
-	"time"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
+// Log keeps the calls Debug/Info/Warn/Error(id, str) writing in the structured logger,
+// id of a crypto goes to the field crypto_id, any other id (MONGODB, REDIS, asset...) to id.
 type Log struct{}
 
-func (l Log) colorReset() string {
-	return string("\033[0m")
+type LogConfig struct {
+	Level  string `json:"level"`  // debug, info, warn or error
+	Format string `json:"format"` // json or console
+	Color  bool   `json:"color"`  // ANSI colors of levels, only in console
+}
+
+func DefaultLogConfig() LogConfig {
+	return LogConfig{Level: "info", Format: "json"}
+}
+
+// Level can be changed while application is running, see LogLevelHandler
+var logLevel = zap.NewAtomicLevel()
+
+var baseMu sync.RWMutex
+var base = newBaseLogger(DefaultLogConfig(), zapcore.Lock(os.Stdout))
+
+// LoadLogConfig reads section "log" of CONFIG_FILE and the env vars LOG_*,
+// LOG_DEBUG=enable is still accepted when LOG_LEVEL is empty
+func LoadLogConfig() (LogConfig, error) {
+	cfg := DefaultLogConfig()
+
+	err := LoadConfigFile("log", &cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	if os.Getenv("LOG_DEBUG") == "enable" {
+		cfg.Level = "debug"
+	}
+
+	envs := map[string]interface{}{
+		"LOG_LEVEL":  &cfg.Level,
+		"LOG_FORMAT": &cfg.Format,
+		"LOG_COLOR":  &cfg.Color,
+	}
+	for key, target := range envs {
+		err = SetFromEnv(key, target)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	if cfg.Format != "json" && cfg.Format != "console" {
+		return cfg, errors.New("log format is invalid: " + cfg.Format)
+	}
+
+	_, err = zapcore.ParseLevel(cfg.Level)
+	if err != nil {
+		return cfg, errors.New("log level is invalid: " + cfg.Level)
+	}
+
+	return cfg, nil
+}
+
+// SetupLog replaces the logger of all packages
+func SetupLog(cfg LogConfig) {
+	SetLogOutput(cfg, zapcore.Lock(os.Stdout))
+}
+
+// SetLogOutput is SetupLog writing in out
+func SetLogOutput(cfg LogConfig, out zapcore.WriteSyncer) {
+	logger := newBaseLogger(cfg, out)
+
+	baseMu.Lock()
+	base = logger
+	baseMu.Unlock()
+}
+
+func newBaseLogger(cfg LogConfig, out zapcore.WriteSyncer) *zap.Logger {
+	level, err := zapcore.ParseLevel(cfg.Level)
+	if err != nil {
+		level = zapcore.InfoLevel
+	}
+	logLevel.SetLevel(level)
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "time"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	var encoder zapcore.Encoder
+	if cfg.Format == "console" {
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		if cfg.Color {
+			encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	} else {
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	}
+
+	return zap.New(zapcore.NewCore(encoder, out, logLevel))
+}
+
+// Logger returns the structured logger, to log with fields
+func Logger() *zap.Logger {
+	baseMu.RLock()
+	defer baseMu.RUnlock()
+	return base
 }
-func (l Log) colorRed() string {
-	return string("\033[31m")
+
+// SyncLog writes the buffered logs, called before application ends
+func SyncLog() {
+	_ = Logger().Sync()
 }
-func (l Log) colorGreen() string {
-	return string("\033[32m")
+
+// LogLevelHandler is the http handler of level: GET returns it and PUT {"level":"debug"} changes it
+func LogLevelHandler() *zap.AtomicLevel {
+	return &logLevel
 }
-func (l Log) colorYellow() string {
-	return string("\033[33m")
+
+type loggerKey struct{}
+
+// logger of one request, fields can be added while the request is running
+type requestLogger struct {
+	mu       sync.Mutex
+	logger   *zap.Logger
+	cryptoId string // already in fields, not repeated by the logs of the id
 }
-func (l Log) colorBlue() string {
-	return string("\033[34m")
+
+// ContextWithLogger keeps in ctx the logger with the fields of request
+func ContextWithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, &requestLogger{logger: logger})
 }
-func (l Log) colorPurple() string {
-	return string("\033[35m")
+
+// LoggerFromContext returns the logger of request or the base logger
+func LoggerFromContext(ctx context.Context) *zap.Logger {
+	holder, ok := ctx.Value(loggerKey{}).(*requestLogger)
+	if !ok {
+		return Logger()
+	}
+
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	return holder.logger
 }
-func (l Log) colorCyan() string {
-	return string("\033[36m")
+
+// AddLogFields adds fields to logger of request, as the caller known after authentication
+func AddLogFields(ctx context.Context, fields ...zap.Field) {
+	holder, ok := ctx.Value(loggerKey{}).(*requestLogger)
+	if !ok {
+		return
+	}
+
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	holder.logger = holder.logger.With(fields...)
 }
-func (l Log) colorWhite() string {
-	return string("\033[37m")
+
+// AddLogCryptoId adds the field crypto_id to logger of request, the logs of the same id do not repeat it
+func AddLogCryptoId(ctx context.Context, id string) {
+	holder, ok := ctx.Value(loggerKey{}).(*requestLogger)
+	if !ok || id == "" {
+		return
+	}
+
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	if holder.cryptoId == "" {
+		holder.cryptoId = id
+		holder.logger = holder.logger.With(zap.String("crypto_id", id))
+	}
 }
 
-func isId(id string) string {
-	var isId = "[" + id + "] "
-	if id == "" {
-		isId = " "
+// Logger of request and the field of id when the request has not it yet
+func contextLogger(ctx context.Context, id string) (*zap.Logger, []zap.Field) {
+	holder, ok := ctx.Value(loggerKey{}).(*requestLogger)
+	if !ok {
+		return Logger(), idField(id)
 	}
-	return isId
+
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	if id == holder.cryptoId {
+		return holder.logger, nil
+	}
+	return holder.logger, idField(id)
 }
 
-func (l *Log) Debug(id string, str string) {
-	if os.Getenv("LOG_DEBUG") == "enable" {
-		fmt.Println("[" + time.Now().Format("2006-01-02 15:04:05.000") + "][DEBUG]" + isId(id) + str)
+func idField(id string) []zap.Field {
+	switch {
+	case id == "":
+		return nil
+	case primitive.IsValidObjectID(id):
+		return []zap.Field{zap.String("crypto_id", id)}
+	default:
+		return []zap.Field{zap.String("id", id)}
 	}
 }
 
+func (l *Log) Debug(id string, str string) {
+	Logger().Debug(str, idField(id)...)
+}
+
 func (l *Log) Info(id string, str string) {
-	fmt.Println("[" + time.Now().Format("2006-01-02 15:04:05.000") + "][INFO]" + isId(id) + str)
+	Logger().Info(str, idField(id)...)
 }
 
 func (l *Log) Warn(id string, str string) {
-	fmt.Println(l.colorYellow() + "[" + time.Now().Format("2006-01-02 15:04:05.000") + "][WARN]" + isId(id) + str + l.colorReset())
+	Logger().Warn(str, idField(id)...)
 }
 
 func (l *Log) Error(id string, str string) {
-	fmt.Println(l.colorRed() + "[" + time.Now().Format("2006-01-02 15:04:05.000") + "][ERROR]" + isId(id) + str + l.colorReset())
+	Logger().Error(str, idField(id)...)
+}
+
+// The Context variants log with the fields of request in ctx: request_id, rpc_method, crypto_id...
+
+func (l *Log) DebugContext(ctx context.Context, id string, str string) {
+	logger, fields := contextLogger(ctx, id)
+	logger.Debug(str, fields...)
+}
+
+func (l *Log) InfoContext(ctx context.Context, id string, str string) {
+	logger, fields := contextLogger(ctx, id)
+	logger.Info(str, fields...)
+}
+
+func (l *Log) WarnContext(ctx context.Context, id string, str string) {
+	logger, fields := contextLogger(ctx, id)
+	logger.Warn(str, fields...)
+}
+
+func (l *Log) ErrorContext(ctx context.Context, id string, str string) {
+	logger, fields := contextLogger(ctx, id)
+	logger.Error(str, fields...)
 }
 
+// Fatal logs and ends the application with exit code 1
 func (l *Log) Fatal(id string, str string, err error) {
-	fmt.Println(l.colorRed() + "[" + time.Now().Format("2006-01-02 15:04:05.000") + "][FATAL]" + isId(id) + str + l.colorReset())
-	panic(err)
+	fields := idField(id)
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	Logger().Fatal(str, fields...)
 }
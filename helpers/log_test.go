@@ -0,0 +1,144 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Help function to write the logs in buffer, returns each line decoded
+func captureLog(t *testing.T, cfg LogConfig) (*bytes.Buffer, func() []map[string]interface{}) {
+	buffer := &bytes.Buffer{}
+	SetLogOutput(cfg, zapcore.AddSync(buffer))
+	t.Cleanup(func() { SetupLog(DefaultLogConfig()) })
+
+	return buffer, func() []map[string]interface{} {
+		lines := []map[string]interface{}{}
+		for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			entry := map[string]interface{}{}
+			require.Nil(t, json.Unmarshal([]byte(line), &entry))
+			lines = append(lines, entry)
+		}
+		return lines
+	}
+}
+
+// Testing shim writes json with level, message and id fields
+func TestLogWithJson(t *testing.T) {
+	_, lines := captureLog(t, DefaultLogConfig())
+	logger := &Log{}
+
+	logger.Info("62a5d5d3f4e5a2b8f4e5a2b8", "Crypto found")
+	logger.Error("REDIS", "Error to set cache")
+
+	entries := lines()
+	require.Len(t, entries, 2)
+	require.Equal(t, "info", entries[0]["level"])
+	require.Equal(t, "Crypto found", entries[0]["msg"])
+	require.Equal(t, "62a5d5d3f4e5a2b8f4e5a2b8", entries[0]["crypto_id"])
+	require.Equal(t, "error", entries[1]["level"])
+	require.Equal(t, "REDIS", entries[1]["id"])
+}
+
+// Testing the context variants write the fields of request, crypto_id of request is not repeated
+func TestLogWithContext(t *testing.T) {
+	buffer, lines := captureLog(t, DefaultLogConfig())
+	logger := &Log{}
+	ctx := ContextWithLogger(context.Background(), Logger().With(zap.String("request_id", "abc123")))
+	AddLogCryptoId(ctx, "62a5d5d3f4e5a2b8f4e5a2b8")
+
+	logger.InfoContext(ctx, "62a5d5d3f4e5a2b8f4e5a2b8", "Crypto found")
+	logger.ErrorContext(ctx, "MONGODB", "Error in find")
+	logger.WarnContext(context.Background(), "", "Without request")
+
+	entries := lines()
+	require.Len(t, entries, 3)
+	require.Equal(t, "abc123", entries[0]["request_id"])
+	require.Equal(t, "62a5d5d3f4e5a2b8f4e5a2b8", entries[0]["crypto_id"])
+	require.Equal(t, 2, strings.Count(buffer.String(), "crypto_id"))
+	require.Equal(t, "abc123", entries[1]["request_id"])
+	require.Equal(t, "MONGODB", entries[1]["id"])
+	require.Nil(t, entries[2]["request_id"])
+}
+
+// Testing debug is written only after level changes
+func TestLogLevelChange(t *testing.T) {
+	_, lines := captureLog(t, DefaultLogConfig())
+	logger := &Log{}
+
+	logger.Debug("", "hidden")
+	LogLevelHandler().SetLevel(zapcore.DebugLevel)
+	logger.Debug("", "visible")
+
+	entries := lines()
+	require.Len(t, entries, 1)
+	require.Equal(t, "visible", entries[0]["msg"])
+}
+
+// Testing http of level: PUT changes it, invalid level and other methods are refused without change
+func TestLogLevelHandler(t *testing.T) {
+	captureLog(t, DefaultLogConfig())
+
+	request := func(method string, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		LogLevelHandler().ServeHTTP(recorder, httptest.NewRequest(method, "/loglevel", strings.NewReader(body)))
+		return recorder
+	}
+
+	response := request(http.MethodPut, `{"level":"verbose"}`)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+	require.Equal(t, zapcore.InfoLevel, LogLevelHandler().Level())
+
+	response = request(http.MethodPost, `{"level":"debug"}`)
+	require.Equal(t, http.StatusMethodNotAllowed, response.Code)
+	require.Equal(t, zapcore.InfoLevel, LogLevelHandler().Level())
+
+	response = request(http.MethodPut, `{"level":"debug"}`)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, zapcore.DebugLevel, LogLevelHandler().Level())
+
+	response = request(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, response.Code)
+	require.JSONEq(t, `{"level":"debug"}`, response.Body.String())
+}
+
+// Testing console without color has no ANSI codes
+func TestLogConsoleWithoutColor(t *testing.T) {
+	buffer, _ := captureLog(t, LogConfig{Level: "info", Format: "console"})
+
+	(&Log{}).Warn("", "some warning")
+
+	require.Contains(t, buffer.String(), "WARN")
+	require.NotContains(t, buffer.String(), "\033[")
+}
+
+// Testing LOG_DEBUG=enable is kept and LOG_LEVEL overrides it
+func TestLoadLogConfig(t *testing.T) {
+	t.Setenv(ConfigFileEnv, "")
+	t.Setenv("LOG_DEBUG", "enable")
+
+	cfg, err := LoadLogConfig()
+	require.Nil(t, err)
+	require.Equal(t, "debug", cfg.Level)
+
+	t.Setenv("LOG_LEVEL", "warn")
+	cfg, err = LoadLogConfig()
+	require.Nil(t, err)
+	require.Equal(t, "warn", cfg.Level)
+
+	t.Setenv("LOG_FORMAT", "xml")
+	_, err = LoadLogConfig()
+	require.NotNil(t, err)
+	require.Equal(t, "log format is invalid: xml", err.Error())
+}
@@ -2,15 +2,29 @@ package helpers
 
 import (
 	"api-desafio-kvr/proto"
+	"api-desafio-kvr/repositories"
 	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 var OnlyLetter = regexp.MustCompile(`^[a-z A-Z]+$`).MatchString
 
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+
+	// PurgeDeleted removes the cryptos deleted before it
+	DefaultRetentionDays = 30
+
+	// WatchCryptos with more ids uses all
+	MaxWatchIds = 100
+)
+
 func ValidatorInCreateCrypto(req *proto.CreateCryptoReq) (err error) {
 	err = NameValidator(req.GetName())
 	if err != nil {
@@ -65,9 +79,165 @@ func ValidatorListAllCryptos(req *proto.SortCryptosReq) error {
 		return err
 	}
 
+	err = PageSizeValidator(req.GetPageSize())
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
+func ValidatorSearchCryptos(req *proto.SearchCryptosReq) error {
+	if req.GetAssetId() != "" {
+		err := AssetValidator(req.GetAssetId())
+		if err != nil {
+			return err
+		}
+	}
+
+	if req.MinPriceUsd != nil {
+		err := PriceValidator(req.GetMinPriceUsd())
+		if err != nil {
+			return err
+		}
+	}
+
+	if req.MaxPriceUsd != nil {
+		err := PriceValidator(req.GetMaxPriceUsd())
+		if err != nil {
+			return err
+		}
+	}
+
+	if req.MinPriceUsd != nil && req.MaxPriceUsd != nil && req.GetMinPriceUsd() > req.GetMaxPriceUsd() {
+		return errors.New("price range is invalid: min_price_usd is bigger than max_price_usd")
+	}
+
+	if req.MinVotes != nil && req.MaxVotes != nil && req.GetMinVotes() > req.GetMaxVotes() {
+		return errors.New("votes range is invalid: min_votes is bigger than max_votes")
+	}
+
+	// empty field then sort by name
+	if req.GetFieldSort() != "" {
+		err := SortValidator(req.GetFieldSort())
+		if err != nil {
+			return err
+		}
+	}
+
+	return PageSizeValidator(req.GetPageSize())
+}
+
+// Intervals of GetPriceHistory, raw returns each price saved
+var PriceIntervals = map[string]time.Duration{
+	"raw": 0,
+	"1h":  time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// Range of GetPriceHistory when from is empty
+const DefaultPriceRange = 24 * time.Hour
+
+// ValidatorPriceHistory returns the range [from, to) and the interval of request, empty fields get the defaults
+func ValidatorPriceHistory(req *proto.PriceHistoryReq, now time.Time) (from time.Time, to time.Time, interval time.Duration, err error) {
+	err = IdValidator(req.GetId())
+	if err != nil {
+		return from, to, interval, err
+	}
+
+	to = now
+	if req.GetTo() != "" {
+		to, err = time.Parse(time.RFC3339, req.GetTo())
+		if err != nil {
+			return from, to, interval, errors.New("to is invalid, use RFC3339: " + req.GetTo())
+		}
+	}
+
+	from = to.Add(-DefaultPriceRange)
+	if req.GetFrom() != "" {
+		from, err = time.Parse(time.RFC3339, req.GetFrom())
+		if err != nil {
+			return from, to, interval, errors.New("from is invalid, use RFC3339: " + req.GetFrom())
+		}
+	}
+
+	if !from.Before(to) {
+		return from, to, interval, errors.New("time range is invalid: from is not before to")
+	}
+
+	name := req.GetInterval()
+	if name == "" {
+		name = "raw"
+	}
+	interval, ok := PriceIntervals[name]
+	if !ok {
+		return from, to, interval, errors.New("interval is invalid, use raw, 1h or 1d: " + name)
+	}
+
+	return from, to, interval, nil
+}
+
+// ValidatorListAuditEvents returns the filter of request, empty fields are not filtered
+func ValidatorListAuditEvents(req *proto.ListAuditEventsReq) (filter repositories.AuditParams, err error) {
+	if req.GetCryptoId() != "" {
+		err = IdValidator(req.GetCryptoId())
+		if err != nil {
+			return filter, err
+		}
+		filter.CryptoId, _ = primitive.ObjectIDFromHex(req.GetCryptoId())
+	}
+
+	filter.Actor = req.GetActor()
+
+	if req.GetFrom() != "" {
+		filter.From, err = time.Parse(time.RFC3339, req.GetFrom())
+		if err != nil {
+			return filter, errors.New("from is invalid, use RFC3339: " + req.GetFrom())
+		}
+	}
+
+	if req.GetTo() != "" {
+		filter.To, err = time.Parse(time.RFC3339, req.GetTo())
+		if err != nil {
+			return filter, errors.New("to is invalid, use RFC3339: " + req.GetTo())
+		}
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, errors.New("time range is invalid: from is not before to")
+	}
+
+	err = PageSizeValidator(req.GetPageSize())
+	if err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// ValidatorWatchCryptos accepts a list of ids or all, not both
+func ValidatorWatchCryptos(req *proto.WatchCryptosReq) error {
+	if req.GetAll() {
+		if len(req.GetIds()) > 0 {
+			return errors.New("ids must be empty when all is true")
+		}
+		return SinceSequenceValidator(req.SinceSequence)
+	}
+
+	if len(req.GetIds()) == 0 || len(req.GetIds()) > MaxWatchIds {
+		return errors.New("ids must have from 1 to " + strconv.Itoa(MaxWatchIds) + " ids, or use all")
+	}
+
+	for _, id := range req.GetIds() {
+		err := IdValidator(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return SinceSequenceValidator(req.SinceSequence)
+}
+
 func IdValidator(id string) error {
 	_, err := primitive.ObjectIDFromHex(id)
 	if id == "" || len(id) <= 2 || err != nil {
@@ -111,3 +281,27 @@ func OrderByValidator(orderBy bool) error {
 	}
 	return nil
 }
+
+// retention_days = 0 is valid, then DefaultRetentionDays is used
+func RetentionValidator(days int32) error {
+	if days < 0 {
+		return errors.New("retention_days is invalid: " + strconv.Itoa(int(days)))
+	}
+	return nil
+}
+
+// since_sequence is optional, 0 replays all events of log
+func SinceSequenceValidator(since *int64) error {
+	if since != nil && *since < 0 {
+		return errors.New("since_sequence is invalid: " + strconv.FormatInt(*since, 10))
+	}
+	return nil
+}
+
+// page_size = 0 is valid, then DefaultPageSize is used
+func PageSizeValidator(size int32) error {
+	if size < 0 || size > MaxPageSize {
+		return errors.New("page_size is invalid: " + strconv.Itoa(int(size)))
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package helpers
 import (
 	"api-desafio-kvr/proto"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -142,6 +143,96 @@ func TestValidatorListAllCryptosWithSuccess(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestValidatorListAllCryptosWithPageSizeInvalid(t *testing.T) {
+	sortParams := returnMockProtoModelToSortCryptos()
+	sortParams.PageSize = MaxPageSize + 1
+
+	err := ValidatorListAllCryptos(&sortParams)
+	require.NotNil(t, err)
+	require.Equal(t, "page_size is invalid: 501", err.Error())
+}
+
+func TestValidatorSearchCryptosWithAssetIdInvalid(t *testing.T) {
+	search := proto.SearchCryptosReq{AssetId: "a"}
+
+	err := ValidatorSearchCryptos(&search)
+	require.NotNil(t, err)
+	require.Equal(t, "asset_id is invalid: a", err.Error())
+}
+
+func TestValidatorSearchCryptosWithVotesRangeInvalid(t *testing.T) {
+	minVotes, maxVotes := int32(5), int32(1)
+	search := proto.SearchCryptosReq{MinVotes: &minVotes, MaxVotes: &maxVotes}
+
+	err := ValidatorSearchCryptos(&search)
+	require.NotNil(t, err)
+	require.Equal(t, "votes range is invalid: min_votes is bigger than max_votes", err.Error())
+}
+
+func TestValidatorSearchCryptosWithPageSizeInvalid(t *testing.T) {
+	search := proto.SearchCryptosReq{PageSize: -1}
+
+	err := ValidatorSearchCryptos(&search)
+	require.NotNil(t, err)
+	require.Equal(t, "page_size is invalid: -1", err.Error())
+}
+
+func TestValidatorSearchCryptosWithEmptySuccess(t *testing.T) {
+	search := proto.SearchCryptosReq{}
+
+	err := ValidatorSearchCryptos(&search)
+	require.Nil(t, err)
+}
+
+func TestValidatorPriceHistoryWithDefaults(t *testing.T) {
+	now := time.Date(2022, 6, 2, 12, 0, 0, 0, time.UTC)
+	req := proto.PriceHistoryReq{Id: primitive.NewObjectID().Hex()}
+
+	from, to, interval, err := ValidatorPriceHistory(&req, now)
+	require.Nil(t, err)
+	require.Equal(t, now.Add(-DefaultPriceRange), from)
+	require.Equal(t, now, to)
+	require.Equal(t, time.Duration(0), interval)
+}
+
+func TestValidatorPriceHistoryWithRangeInvalid(t *testing.T) {
+	req := proto.PriceHistoryReq{
+		Id:   primitive.NewObjectID().Hex(),
+		From: "2022-06-02T00:00:00Z",
+		To:   "2022-06-01T00:00:00Z",
+	}
+
+	_, _, _, err := ValidatorPriceHistory(&req, time.Now())
+	require.NotNil(t, err)
+	require.Equal(t, "time range is invalid: from is not before to", err.Error())
+}
+
+func TestValidatorPriceHistoryWithFromInvalid(t *testing.T) {
+	req := proto.PriceHistoryReq{Id: primitive.NewObjectID().Hex(), From: "2022-06-01"}
+
+	_, _, _, err := ValidatorPriceHistory(&req, time.Now())
+	require.NotNil(t, err)
+	require.Equal(t, "from is invalid, use RFC3339: 2022-06-01", err.Error())
+}
+
+func TestValidatorListAuditEventsWithCryptoIdInvalid(t *testing.T) {
+	req := proto.ListAuditEventsReq{CryptoId: "123abc"}
+
+	_, err := ValidatorListAuditEvents(&req)
+	require.NotNil(t, err)
+	require.Equal(t, "id is invalid: 123abc err: the provided hex string is not a valid ObjectID", err.Error())
+}
+
+func TestValidatorListAuditEventsWithEmptySuccess(t *testing.T) {
+	req := proto.ListAuditEventsReq{}
+
+	filter, err := ValidatorListAuditEvents(&req)
+	require.Nil(t, err)
+	require.True(t, filter.CryptoId.IsZero())
+	require.True(t, filter.From.IsZero())
+	require.True(t, filter.To.IsZero())
+}
+
 func TestIdValidatorWithInvalid(t *testing.T) {
 	id := "123abc"
 
@@ -263,3 +354,50 @@ func TestOrderByValidatorWithSuccess(t *testing.T) {
 	err := OrderByValidator(field)
 	require.Nil(t, err)
 }
+
+func TestPageSizeValidatorWithNegativeInvalid(t *testing.T) {
+	size := int32(-1)
+
+	err := PageSizeValidator(size)
+	require.NotNil(t, err)
+	require.Equal(t, "page_size is invalid: -1", err.Error())
+}
+
+func TestPageSizeValidatorWithZeroSuccess(t *testing.T) {
+	size := int32(0)
+
+	err := PageSizeValidator(size)
+	require.Nil(t, err)
+}
+
+func TestValidatorWatchCryptosWithoutIdsInvalid(t *testing.T) {
+	err := ValidatorWatchCryptos(&proto.WatchCryptosReq{})
+	require.NotNil(t, err)
+	require.Equal(t, "ids must have from 1 to 100 ids, or use all", err.Error())
+
+	err = ValidatorWatchCryptos(&proto.WatchCryptosReq{Ids: []string{"123abc"}})
+	require.NotNil(t, err)
+}
+
+func TestValidatorWatchCryptosWithSuccess(t *testing.T) {
+	err := ValidatorWatchCryptos(&proto.WatchCryptosReq{All: true})
+	require.Nil(t, err)
+
+	err = ValidatorWatchCryptos(&proto.WatchCryptosReq{Ids: []string{"62a0d1a5c4a1b2c3d4e5f607"}})
+	require.Nil(t, err)
+}
+
+func TestSinceSequenceValidatorWithNegativeInvalid(t *testing.T) {
+	since := int64(-1)
+
+	err := SinceSequenceValidator(&since)
+	require.NotNil(t, err)
+	require.Equal(t, "since_sequence is invalid: -1", err.Error())
+}
+
+func TestSinceSequenceValidatorWithSuccess(t *testing.T) {
+	since := int64(0)
+
+	require.Nil(t, SinceSequenceValidator(&since))
+	require.Nil(t, SinceSequenceValidator(nil))
+}
@@ -0,0 +1,65 @@
+package interceptors
+
+import (
+	"api-desafio-kvr/auth"
+	"api-desafio-kvr/helpers"
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryAuth authenticates the caller by metadata and checks the requirement of rpc in policy,
+// the identity is kept in context to handlers (auth.FromContext) and to logs (field subject)
+func UnaryAuth(authenticator *auth.Authenticator, policy auth.Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, authenticator, policy.For(info.FullMethod))
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamAuth(authenticator *auth.Authenticator, policy auth.Policy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(stream.Context(), authenticator, policy.For(info.FullMethod))
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func authorize(ctx context.Context, authenticator *auth.Authenticator, requirement auth.Requirement) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	identity, found, err := authenticator.Authenticate(md)
+	// invalid credentials are refused even in public rpcs
+	if err != nil {
+		return ctx, status.Errorf(16, err.Error())
+	}
+
+	if found {
+		ctx = auth.ContextWithIdentity(ctx, identity)
+		helpers.AddLogFields(ctx, zap.String("subject", identity.Subject), zap.String("auth_method", identity.Method))
+	}
+
+	switch requirement {
+	case auth.Authenticated:
+		if !found {
+			return ctx, status.Errorf(16, "credentials not found, send metadata authorization or x-api-key")
+		}
+	case auth.Admin:
+		if !found {
+			return ctx, status.Errorf(16, "credentials not found, send metadata authorization or x-api-key")
+		}
+		if !identity.HasRole(auth.RoleAdmin) {
+			return ctx, status.Errorf(7, "role "+auth.RoleAdmin+" is required")
+		}
+	}
+
+	return ctx, nil
+}
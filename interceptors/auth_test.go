@@ -0,0 +1,68 @@
+package interceptors
+
+import (
+	"api-desafio-kvr/auth"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Help function to run unary auth with api key in metadata, returns identity seen by handler
+func runAuth(t *testing.T, method string, key string) (auth.Identity, bool, error) {
+	authenticator, err := auth.New(auth.Config{APIKeys: []auth.APIKey{
+		{Key: "key-admin", Subject: "ops", Roles: []string{auth.RoleAdmin}},
+		{Key: "key-user", Subject: "voter-1", Roles: []string{auth.RoleUser}},
+	}})
+	require.Nil(t, err)
+
+	ctx := context.Background()
+	if key != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(auth.APIKeyMetadataKey, key))
+	}
+
+	var identity auth.Identity
+	var found bool
+	info := &grpc.UnaryServerInfo{FullMethod: "/proto.EndPointCryptos/" + method}
+	_, err = UnaryAuth(authenticator, auth.DefaultPolicy())(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		identity, found = auth.FromContext(ctx)
+		return nil, nil
+	})
+	return identity, found, err
+}
+
+// Testing writes need admin
+func TestAuthWithAdmin(t *testing.T) {
+	_, _, err := runAuth(t, "CreateCrypto", "")
+	require.Equal(t, "rpc error: code = Unauthenticated desc = credentials not found, send metadata authorization or x-api-key", err.Error())
+
+	_, _, err = runAuth(t, "CreateCrypto", "key-user")
+	require.Equal(t, "rpc error: code = PermissionDenied desc = role admin is required", err.Error())
+
+	identity, found, err := runAuth(t, "CreateCrypto", "key-admin")
+	require.Nil(t, err)
+	require.True(t, found)
+	require.Equal(t, "ops", identity.Subject)
+}
+
+// Testing votes need any identity
+func TestAuthWithVote(t *testing.T) {
+	_, _, err := runAuth(t, "Upvote", "")
+	require.NotNil(t, err)
+
+	identity, _, err := runAuth(t, "Upvote", "key-user")
+	require.Nil(t, err)
+	require.Equal(t, "voter-1", identity.Subject)
+}
+
+// Testing reads are public, but invalid credentials are refused
+func TestAuthWithPublic(t *testing.T) {
+	_, found, err := runAuth(t, "ListAllCryptos", "")
+	require.Nil(t, err)
+	require.False(t, found)
+
+	_, _, err = runAuth(t, "ListAllCryptos", "wrong")
+	require.Equal(t, "rpc error: code = Unauthenticated desc = credentials are invalid", err.Error())
+}
@@ -0,0 +1,119 @@
+package interceptors
+
+import (
+	"api-desafio-kvr/helpers"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIdMetadataKey is read from client or created, and returned in header of response
+const RequestIdMetadataKey = "x-request-id"
+
+// requests with id of crypto, as FindCryptoReq and VoteReq
+type idRequest interface {
+	GetId() string
+}
+
+// UnaryLogging logs one line by rpc with request id, method, crypto id, latency and grpc code
+func UnaryLogging() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx = helpers.ContextWithLogger(ctx, requestLogger(ctx, info.FullMethod))
+		if r, ok := req.(idRequest); ok {
+			helpers.AddLogCryptoId(ctx, r.GetId())
+		}
+		resp, err := handler(ctx, req)
+
+		logRequest(helpers.LoggerFromContext(ctx), start, err)
+		return resp, err
+	}
+}
+
+// StreamLogging logs when the stream ends, latency is the time of stream
+func StreamLogging() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		logger := requestLogger(stream.Context(), info.FullMethod)
+
+		ctx := helpers.ContextWithLogger(stream.Context(), logger)
+		err := handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+
+		logRequest(helpers.LoggerFromContext(ctx), start, err)
+		return err
+	}
+}
+
+// contextStream replaces the context of stream, to pass values to handler
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func requestLogger(ctx context.Context, method string) *zap.Logger {
+	requestId := requestIdFromContext(ctx)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIdMetadataKey, requestId))
+
+	logger := helpers.Logger().With(
+		zap.String("request_id", requestId),
+		zap.String("rpc_method", method),
+	)
+
+	// links the logs to the trace of request
+	spanContext := trace.SpanContextFromContext(ctx)
+	if spanContext.HasTraceID() {
+		logger = logger.With(zap.String("trace_id", spanContext.TraceID().String()))
+	}
+	return logger
+}
+
+func logRequest(logger *zap.Logger, start time.Time, err error) {
+	code := status.Code(err)
+	level := zapcore.InfoLevel
+	switch code {
+	case codes.OK, codes.Canceled:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		level = zapcore.ErrorLevel
+	default:
+		level = zapcore.WarnLevel
+	}
+
+	fields := []zap.Field{
+		zap.Duration("latency", time.Since(start)),
+		zap.String("grpc_code", code.String()),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+
+	if entry := logger.Check(level, "Request finished"); entry != nil {
+		entry.Write(fields...)
+	}
+}
+
+func requestIdFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if ok {
+		values := md.Get(RequestIdMetadataKey)
+		if len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,65 @@
+package interceptors
+
+import (
+	"api-desafio-kvr/helpers"
+	"api-desafio-kvr/proto"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Help function to write the logs in buffer
+func captureLog(t *testing.T) *bytes.Buffer {
+	buffer := &bytes.Buffer{}
+	helpers.SetLogOutput(helpers.DefaultLogConfig(), zapcore.AddSync(buffer))
+	t.Cleanup(func() { helpers.SetupLog(helpers.DefaultLogConfig()) })
+	return buffer
+}
+
+// Testing unary logs request id of client, method, crypto id and grpc code, the logs of handler too
+func TestUnaryLogging(t *testing.T) {
+	buffer := captureLog(t)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIdMetadataKey, "req-1"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/proto.EndPointCryptos/FindCrypto"}
+
+	_, err := UnaryLogging()(ctx, &proto.FindCryptoReq{Id: "62a5d5d3f4e5a2b8f4e5a2b8"}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			(&helpers.Log{}).InfoContext(ctx, "", "inside handler")
+			return nil, status.Errorf(5, "crypto not found")
+		})
+	require.NotNil(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	handler := map[string]interface{}{}
+	require.Nil(t, json.Unmarshal(lines[0], &handler))
+	require.Equal(t, "req-1", handler["request_id"])
+	require.Equal(t, "/proto.EndPointCryptos/FindCrypto", handler["rpc_method"])
+	require.Equal(t, "62a5d5d3f4e5a2b8f4e5a2b8", handler["crypto_id"])
+
+	entry := map[string]interface{}{}
+	require.Nil(t, json.Unmarshal(lines[1], &entry))
+	require.Equal(t, "warn", entry["level"])
+	require.Equal(t, "req-1", entry["request_id"])
+	require.Equal(t, "/proto.EndPointCryptos/FindCrypto", entry["rpc_method"])
+	require.Equal(t, "62a5d5d3f4e5a2b8f4e5a2b8", entry["crypto_id"])
+	require.Equal(t, "NotFound", entry["grpc_code"])
+	require.Contains(t, entry, "latency")
+}
+
+// Testing request id is created when client does not send it
+func TestRequestIdCreated(t *testing.T) {
+	first := requestIdFromContext(context.Background())
+	second := requestIdFromContext(context.Background())
+
+	require.Len(t, first, 16)
+	require.NotEqual(t, first, second)
+}
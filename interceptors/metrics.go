@@ -0,0 +1,39 @@
+package interceptors
+
+import (
+	"api-desafio-kvr/metrics"
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryMetrics counts the rpcs by method and grpc code and observes the latency
+func UnaryMetrics() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observeRequest(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamMetrics is UnaryMetrics to streams, latency is the time of stream. The streams open are counted by method
+func StreamMetrics() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		active := metrics.ActiveStreams.WithLabelValues(info.FullMethod)
+		active.Inc()
+		defer active.Dec()
+
+		err := handler(srv, stream)
+		observeRequest(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func observeRequest(method string, start time.Time, err error) {
+	metrics.RequestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	metrics.RequestsTotal.WithLabelValues(method, status.Code(err).String()).Inc()
+}
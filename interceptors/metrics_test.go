@@ -0,0 +1,43 @@
+package interceptors
+
+import (
+	"api-desafio-kvr/metrics"
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Testing unary counts the rpc by method and grpc code
+func TestUnaryMetrics(t *testing.T) {
+	method := "/proto.EndPointCryptos/CreateCrypto"
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+	before := testutil.ToFloat64(metrics.RequestsTotal.WithLabelValues(method, "InvalidArgument"))
+
+	_, err := UnaryMetrics()(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Errorf(3, "name is invalid")
+	})
+	require.NotNil(t, err)
+
+	require.Equal(t, before+1, testutil.ToFloat64(metrics.RequestsTotal.WithLabelValues(method, "InvalidArgument")))
+	require.Equal(t, 1, testutil.CollectAndCount(metrics.RequestDuration, "grpc_server_handling_seconds"))
+}
+
+// Testing the stream is counted as open by method until it ends
+func TestStreamMetricsActiveStreams(t *testing.T) {
+	method := "/proto.EndPointCryptos/WatchCryptos"
+	info := &grpc.StreamServerInfo{FullMethod: method, IsServerStream: true}
+	active := metrics.ActiveStreams.WithLabelValues(method)
+
+	err := StreamMetrics()(nil, nil, info, func(srv interface{}, stream grpc.ServerStream) error {
+		require.Equal(t, float64(1), testutil.ToFloat64(active))
+		require.Equal(t, float64(0), testutil.ToFloat64(metrics.ActiveStreams.WithLabelValues("/proto.EndPointCryptos/MonitorVotes")))
+		return nil
+	})
+
+	require.Nil(t, err)
+	require.Equal(t, float64(0), testutil.ToFloat64(active))
+}
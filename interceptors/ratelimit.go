@@ -0,0 +1,87 @@
+package interceptors
+
+import (
+	"api-desafio-kvr/auth"
+	"api-desafio-kvr/helpers"
+	"api-desafio-kvr/ratelimit"
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RetryAfterMetadataKey has the seconds to wait when the limit is exceeded
+const RetryAfterMetadataKey = "retry-after"
+
+var logger = &helpers.Log{}
+
+// UnaryRateLimit limits the calls of each method by caller: the identity, after auth, or the ip of peer
+func UnaryRateLimit(limiter ratelimit.Limiter, cfg ratelimit.Config) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		err := allow(ctx, limiter, cfg.For(info.FullMethod), info.FullMethod, func(md metadata.MD) error {
+			return grpc.SetHeader(ctx, md)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimit limits the start of streams
+func StreamRateLimit(limiter ratelimit.Limiter, cfg ratelimit.Config) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := allow(stream.Context(), limiter, cfg.For(info.FullMethod), info.FullMethod, stream.SetHeader)
+		if err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+func allow(ctx context.Context, limiter ratelimit.Limiter, limit ratelimit.Limit, method string, setHeader func(metadata.MD) error) error {
+	if limit.Unlimited() {
+		return nil
+	}
+
+	caller := callerKey(ctx)
+	allowed, retryAfter, err := limiter.Allow(ctx, method+":"+caller, limit)
+	// without the backend the calls are not blocked
+	if err != nil {
+		logger.Error("RATELIMIT", "Error in rate limit of "+caller+": "+err.Error())
+		return nil
+	}
+	if allowed {
+		return nil
+	}
+
+	seconds := strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+	_ = setHeader(metadata.Pairs(RetryAfterMetadataKey, seconds))
+
+	logger.Warn("RATELIMIT", "Rate limit exceeded by "+caller+" in "+method)
+	return status.Errorf(8, "rate limit exceeded, retry after "+retryAfter.Round(time.Millisecond).String())
+}
+
+func callerKey(ctx context.Context) string {
+	identity, ok := auth.FromContext(ctx)
+	if ok {
+		return "user:" + identity.Subject
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		return "ip:" + host
+	}
+
+	return "anonymous"
+}
@@ -0,0 +1,73 @@
+package interceptors
+
+import (
+	"api-desafio-kvr/auth"
+	"api-desafio-kvr/ratelimit"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Mock of transport to read the header set by interceptor
+type mockTransportStream struct {
+	header metadata.MD
+}
+
+func (m *mockTransportStream) Method() string { return "" }
+func (m *mockTransportStream) SetHeader(md metadata.MD) error {
+	m.header = metadata.Join(m.header, md)
+	return nil
+}
+func (m *mockTransportStream) SendHeader(md metadata.MD) error { return nil }
+func (m *mockTransportStream) SetTrailer(md metadata.MD) error { return nil }
+
+// Help function to call Upvote through rate limit of 1 call
+func callUpvote(ctx context.Context, limiter ratelimit.Limiter) (*mockTransportStream, error) {
+	cfg := ratelimit.Config{Methods: map[string]ratelimit.Limit{"Upvote": {Rate: 1, Burst: 1}}}
+	transport := &mockTransportStream{}
+	ctx = grpc.NewContextWithServerTransportStream(ctx, transport)
+	info := &grpc.UnaryServerInfo{FullMethod: "/proto.EndPointCryptos/Upvote"}
+
+	_, err := UnaryRateLimit(limiter, cfg)(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	return transport, err
+}
+
+// Testing calls over limit get ResourceExhausted and retry-after
+func TestRateLimitExceeded(t *testing.T) {
+	limiter := ratelimit.NewMemory()
+	ctx := auth.ContextWithIdentity(context.Background(), auth.Identity{Subject: "voter-1"})
+
+	_, err := callUpvote(ctx, limiter)
+	require.Nil(t, err)
+
+	transport, err := callUpvote(ctx, limiter)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "rpc error: code = ResourceExhausted desc = rate limit exceeded, retry after")
+	require.Equal(t, []string{"1"}, transport.header.Get(RetryAfterMetadataKey))
+
+	// other identity is not limited
+	other := auth.ContextWithIdentity(context.Background(), auth.Identity{Subject: "voter-2"})
+	_, err = callUpvote(other, limiter)
+	require.Nil(t, err)
+}
+
+// Testing anonymous caller is limited by ip of peer
+func TestRateLimitByPeer(t *testing.T) {
+	limiter := ratelimit.NewMemory()
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000}})
+	samePeer := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 6000}})
+
+	require.Equal(t, "ip:10.0.0.1", callerKey(ctx))
+
+	_, err := callUpvote(ctx, limiter)
+	require.Nil(t, err)
+	_, err = callUpvote(samePeer, limiter)
+	require.NotNil(t, err)
+}
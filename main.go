@@ -1,41 +1,365 @@
 package main
 
 import (
+	"api-desafio-kvr/auth"
 	"api-desafio-kvr/controllers"
+	"api-desafio-kvr/healthcheck"
 	"api-desafio-kvr/helpers"
+	"api-desafio-kvr/interceptors"
+	"api-desafio-kvr/metrics"
+	"api-desafio-kvr/observer"
 	"api-desafio-kvr/proto"
+	"api-desafio-kvr/ratelimit"
+	"api-desafio-kvr/repositories/cache"
 	"api-desafio-kvr/repositories/migration"
 	"api-desafio-kvr/repositories/mongodb"
+	rds "api-desafio-kvr/repositories/redis"
+	"api-desafio-kvr/tracing"
+	"api-desafio-kvr/watcher"
+	"context"
+	"io"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	goredis "github.com/go-redis/redis"
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
 var logger = &helpers.Log{}
 
+// One pool of connections to redis, shared by cache, rate limit and streams. Nil while none of them uses redis
+var redisClient *goredis.Client
+
 func main() {
 	logger.Info("", "Starting services to application")
-	client, ctx, cancel, _ := mongodb.Connect()
+
+	err := godotenv.Load()
+	if err != nil {
+		logger.Warn("", "File .env not loaded: "+err.Error())
+	}
+
+	logConfig, err := helpers.LoadLogConfig()
+	if err != nil {
+		logger.Fatal("LOG", err.Error(), err)
+	}
+	helpers.SetupLog(logConfig)
+	defer helpers.SyncLog()
+
+	tracingConfig, err := tracing.LoadConfig()
+	if err != nil {
+		logger.Fatal("TRACING", err.Error(), err)
+	}
+	stopTracing, err := tracing.Setup(context.Background(), tracingConfig)
+	if err != nil {
+		logger.Fatal("TRACING", err.Error(), err)
+	}
+
+	mongoConfig, err := mongodb.LoadConfig()
+	if err != nil {
+		logger.Fatal("MONGODB", err.Error(), err)
+	}
+
+	client, err := mongodb.Connect(mongoConfig)
+	if err != nil {
+		logger.Fatal("MONGODB", err.Error(), err)
+	}
+
+	cacheClient := StartCache()
 
 	collection := mongodb.GetDataBase(client)
-	app := &controllers.AppServer{Database: collection}
+	app := &controllers.AppServer{
+		Database: collection,
+		Votes:    mongodb.GetVotesCollection(client),
+		Prices:   mongodb.GetPricesCollection(client),
+		Audit:    mongodb.GetAuditCollection(client),
+		Cache:    cacheClient,
+	}
+
+	// health is NOT_SERVING until the migration is finished
+	controllers.StartHub()
+	StartBackplane()
+	limiter, limitConfig := StartRateLimit()
+	checker := StartHealth(client)
+	server := StartGRPC(app, checker, limiter, limitConfig)
+	admin := StartAdmin()
+
+	// index before migration, so imported cryptos respect unique asset_id
+	err = mongodb.CreateCryptosIndexes(app.Database)
+	if err != nil {
+		logger.Error("", "Error to create indexes of cryptos: "+err.Error())
+	}
+
+	// the migration records the first price of each crypto
+	err = mongodb.CreatePricesIndexes(app.Prices)
+	if err != nil {
+		logger.Error("", "Error to create indexes of price history: "+err.Error())
+	}
+
+	migration.CreateInitialCryptosBulk(context.Background(), app.Database, app.Prices)
+
+	err = mongodb.CreateVotesIndexes(app.Votes)
+	if err != nil {
+		logger.Error("", "Error to create indexes of votes: "+err.Error())
+	}
+
+	err = mongodb.CreateAuditIndexes(app.Audit)
+	if err != nil {
+		logger.Error("", "Error to create indexes of audit events: "+err.Error())
+	}
+
+	changes := StartWatcher(app, client)
+
+	checker.SetReady()
+	checker.Start()
+
+	// SIGTERM is sent by rolling deploys, SIGINT by Ctrl+C
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	received := <-signals
+	logger.Info("", "Signal "+received.String()+" received, shutting down")
 
-	migration.CreateInitialCryptosBulk(app.Database)
+	Shutdown(server, admin, checker, changes, limiter, client, cacheClient)
 
-	controllers.StartChanToStream()
-	StartGRPC(app)
+	// spans of shutdown are exported too
+	err = stopTracing(context.Background())
+	if err != nil {
+		logger.Error("TRACING", "Error to export traces: "+err.Error())
+	}
+}
+
+// Shutdown ends the streams, waits the requests in progress and closes watcher, hub, cache, redis and database, in this order
+func Shutdown(server *grpc.Server, admin *http.Server, checker *healthcheck.Checker, changes *watcher.Watcher, limiter ratelimit.Limiter, client *mongo.Client, cacheClient cache.Cache) {
+	timeout := shutdownTimeout()
+
+	checker.Shutdown()
+	controllers.StopStreams()
+	StopGRPC(server, timeout)
+	StopAdmin(admin, timeout)
+
+	// the watcher publishes to hub and deletes cache
+	if changes != nil {
+		changes.Stop()
+	}
+
+	controllers.StopHub()
+
+	err := cacheClient.Close()
+	if err != nil {
+		logger.Error("CACHE", "Error to close cache: "+err.Error())
+	}
+
+	if closer, ok := limiter.(io.Closer); ok {
+		err = closer.Close()
+		if err != nil {
+			logger.Error("RATELIMIT", "Error to close rate limit: "+err.Error())
+		}
+	}
+
+	// after cache, rate limit and backplane, the users of the pool
+	if redisClient != nil {
+		logger.Info("REDIS", "Closing redis client")
+		err = redisClient.Close()
+		if err != nil {
+			logger.Error("REDIS", "Error to close redis client: "+err.Error())
+		}
+	}
+
+	err = mongodb.Disconnect(client, timeout)
+	if err != nil {
+		logger.Error("MONGODB", "Error to close database connection: "+err.Error())
+	}
+
+	logger.Info("", "Application stopped")
+}
+
+func shutdownTimeout() time.Duration {
+	timeout := helpers.Duration{Duration: 15 * time.Second}
+	err := helpers.SetFromEnv("SHUTDOWN_TIMEOUT", &timeout)
+	if err != nil {
+		logger.Warn("", err.Error())
+	}
+	return timeout.Duration
+}
+
+// Selects the cache by CACHE_DRIVER, redis is only connected when it is used
+func StartCache() cache.Cache {
+	cacheConfig, err := cache.LoadConfig()
+	if err != nil {
+		logger.Fatal("CACHE", err.Error(), err)
+	}
+
+	switch cacheConfig.Driver {
+	case cache.DriverMemory:
+		return cache.NewMemory(cacheConfig.MemorySize, cacheConfig.TTL)
+	case cache.DriverNone:
+		return cache.NewNoop()
+	}
+
+	return rds.NewCache(StartRedis(), cacheConfig.TTL)
+}
+
+// Connects redis in the first use, the next ones share the pool
+func StartRedis() *goredis.Client {
+	if redisClient != nil {
+		return redisClient
+	}
+
+	redisConfig, err := rds.LoadConfig()
+	if err != nil {
+		logger.Fatal("REDIS", err.Error(), err)
+	}
 
-	mongodb.Disconnect(client, ctx, cancel)
+	redisClient = rds.Connect(redisConfig)
+	return redisClient
 }
 
-func StartGRPC(app *controllers.AppServer) {
+// Returns nil when WATCHER_ENABLED is false, then only the writes of this process
+// delete the cache and notify the streams
+func StartWatcher(app *controllers.AppServer, client *mongo.Client) *watcher.Watcher {
+	watcherConfig, err := watcher.LoadConfig()
+	if err != nil {
+		logger.Fatal("WATCHER", err.Error(), err)
+	}
+
+	if !watcherConfig.Enabled {
+		logger.Warn("WATCHER", "Watcher is disabled, changes of other replicas are not seen")
+		return nil
+	}
+
+	if !mongodb.Replicated() {
+		logger.Warn("WATCHER", "MongoDB is a standalone server without change streams, changes of other replicas are not seen")
+		return nil
+	}
+
+	tokens := mongodb.GetResumeTokensCollection(client)
+	err = mongodb.CreateResumeTokensIndexes(tokens)
+	if err != nil {
+		logger.Error("", "Error to create indexes of resume tokens: "+err.Error())
+	}
+
+	// the writes of this process are published by it until the stream opens
+	changes := watcher.New(watcherConfig, app.Database, tokens, app.OnCryptoChange, controllers.UseWatcher)
+	changes.Start()
+	return changes
+}
+
+// With STREAM_BACKPLANE=redis the events reach the streams of all replicas and the log to replay is shared by them
+func StartBackplane() {
+	streamConfig, err := observer.LoadConfig()
+	if err != nil {
+		logger.Fatal("STREAM", err.Error(), err)
+	}
+
+	if streamConfig.Backplane == observer.BackplaneLocal {
+		controllers.SetBackplane(observer.NewLocal(controllers.Hub(), streamConfig.LogSize))
+		return
+	}
+
+	backplane, err := observer.NewRedis(StartRedis(), streamConfig.Channel, streamConfig.LogSize, controllers.Hub())
+	if err != nil {
+		logger.Fatal("STREAM", "Error to subscribe channel "+streamConfig.Channel+": "+err.Error(), err)
+	}
+
+	logger.Info("STREAM", "Streams subscribed to channel "+streamConfig.Channel+" of redis")
+	controllers.SetBackplane(backplane)
+}
+
+// Probes of mongodb and redis, each one with its service name in grpc.health.v1
+func StartHealth(client *mongo.Client) *healthcheck.Checker {
+	healthConfig, err := healthcheck.LoadConfig()
+	if err != nil {
+		logger.Fatal("HEALTH", err.Error(), err)
+	}
+
+	checker := healthcheck.New(healthConfig, proto.EndPointCryptos_ServiceDesc.ServiceName)
+	checker.Add("mongodb", func(ctx context.Context) error {
+		return mongodb.Ping(ctx, client)
+	})
+
+	// only when cache, rate limit or streams use redis
+	if redisClient != nil {
+		checker.Add("redis", func(ctx context.Context) error {
+			return redisClient.WithContext(ctx).Ping().Err()
+		})
+	}
+
+	return checker
+}
+
+// Returns nil when AUTH_ENABLED is false, then all rpcs are open
+func StartAuth() *auth.Authenticator {
+	authConfig, err := auth.LoadConfig()
+	if err != nil {
+		logger.Fatal("AUTH", err.Error(), err)
+	}
+
+	if !authConfig.Enabled {
+		logger.Warn("AUTH", "Auth is disabled, all rpcs are open")
+		return nil
+	}
+
+	authenticator, err := auth.New(authConfig)
+	if err != nil {
+		logger.Fatal("AUTH", err.Error(), err)
+	}
+	return authenticator
+}
+
+// Returns nil when RATELIMIT_ENABLED is false
+func StartRateLimit() (ratelimit.Limiter, ratelimit.Config) {
+	limitConfig, err := ratelimit.LoadConfig()
+	if err != nil {
+		logger.Fatal("RATELIMIT", err.Error(), err)
+	}
+
+	if !limitConfig.Enabled {
+		logger.Warn("RATELIMIT", "Rate limit is disabled")
+		return nil, limitConfig
+	}
+
+	if limitConfig.Backend == ratelimit.BackendMemory {
+		return ratelimit.NewMemory(), limitConfig
+	}
+
+	return ratelimit.NewRedis(StartRedis()), limitConfig
+}
+
+func StartGRPC(app *controllers.AppServer, checker *healthcheck.Checker, limiter ratelimit.Limiter, limitConfig ratelimit.Config) *grpc.Server {
 	logger.Info("", "Starting gRPC service")
 
-	grpc := grpc.NewServer()
-	proto.RegisterEndPointCryptosServer(grpc, app)
-	reflection.Register(grpc)
+	unary := []grpc.UnaryServerInterceptor{otelgrpc.UnaryServerInterceptor(), interceptors.UnaryLogging(), interceptors.UnaryMetrics()}
+	stream := []grpc.StreamServerInterceptor{otelgrpc.StreamServerInterceptor(), interceptors.StreamLogging(), interceptors.StreamMetrics()}
+
+	// auth after logging and metrics, so refused requests are logged and counted
+	authenticator := StartAuth()
+	if authenticator != nil {
+		policy := auth.DefaultPolicy()
+		unary = append(unary, interceptors.UnaryAuth(authenticator, policy))
+		stream = append(stream, interceptors.StreamAuth(authenticator, policy))
+	}
+
+	// rate limit after auth, so the caller is the identity when it exists
+	if limiter != nil {
+		unary = append(unary, interceptors.UnaryRateLimit(limiter, limitConfig))
+		stream = append(stream, interceptors.StreamRateLimit(limiter, limitConfig))
+	}
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+	proto.RegisterEndPointCryptosServer(server, app)
+	healthpb.RegisterHealthServer(server, checker.Server())
+	reflection.Register(server)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -49,8 +373,72 @@ func StartGRPC(app *controllers.AppServer) {
 	}
 
 	logger.Info("", "gRPC service running on the port "+port)
-	err = grpc.Serve(listener)
+	go func() {
+		err := server.Serve(listener)
+		if err != nil {
+			logger.Fatal("", err.Error(), err)
+		}
+	}()
+
+	return server
+}
+
+// StopGRPC stops accepting requests and waits the ones in progress, after timeout they are cancelled
+func StopGRPC(server *grpc.Server, timeout time.Duration) {
+	logger.Info("", "Stopping gRPC service")
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		logger.Info("", "gRPC service stopped")
+	case <-time.After(timeout):
+		logger.Warn("", "gRPC service not stopped in "+timeout.String()+", cancelling requests in progress")
+		server.Stop()
+	}
+}
+
+// StartAdmin serves the http endpoints of operation in ADMIN_ADDR:ADMIN_PORT:
+// /loglevel GET returns the level of log and PUT {"level":"debug"} changes it,
+// /metrics returns the metrics to prometheus.
+// They have no authentication, so the default address is only local
+func StartAdmin() *http.Server {
+	addr := os.Getenv("ADMIN_ADDR")
+	if addr == "" {
+		addr = "127.0.0.1"
+	}
+
+	port := os.Getenv("ADMIN_PORT")
+	if port == "" {
+		port = "9090"
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/loglevel", helpers.LogLevelHandler())
+	mux.Handle("/metrics", metrics.Handler())
+
+	admin := &http.Server{Addr: net.JoinHostPort(addr, port), Handler: mux}
+	go func() {
+		err := admin.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			logger.Error("ADMIN", "Error in admin http server: "+err.Error())
+		}
+	}()
+
+	logger.Info("ADMIN", "Admin http running on "+admin.Addr)
+	return admin
+}
+
+func StopAdmin(admin *http.Server, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := admin.Shutdown(ctx)
 	if err != nil {
-		logger.Fatal("", err.Error(), err)
+		logger.Error("ADMIN", "Error to stop admin http server: "+err.Error())
 	}
 }
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry has the metrics of application, of go runtime and of process
+var Registry = prometheus.NewRegistry()
+
+const (
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheError = "error"
+)
+
+var (
+	RequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "Total of rpcs finished, by method and grpc code.",
+	}, []string{"grpc_method", "grpc_code"})
+
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Latency of rpcs until the response, in streams until the end of stream.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_method"})
+
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_requests_total",
+		Help: "Total of reads in cache, by path (find or list) and result (hit, miss or error).",
+	}, []string{"path", "result"})
+
+	MongoDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mongodb_operation_seconds",
+		Help:    "Latency of operations in mongodb, by operation.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation"})
+
+	ActiveStreams = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "grpc_server_active_streams",
+		Help: "Number of streams open, by method (MonitorVotes, MonitorVotesEvents and WatchCryptos).",
+	}, []string{"grpc_method"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		RequestsTotal,
+		RequestDuration,
+		CacheRequests,
+		MongoDuration,
+		ActiveStreams,
+	)
+}
+
+// Handler serves the metrics in format of prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveMongo is called with defer in start of operation: defer metrics.ObserveMongo("get_by_id", time.Now())
+func ObserveMongo(operation string, start time.Time) {
+	MongoDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+func ObserveCache(path string, result string) {
+	CacheRequests.WithLabelValues(path, result).Inc()
+}
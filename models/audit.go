@@ -0,0 +1,89 @@
+package models
+
+import (
+	"api-desafio-kvr/proto"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Actor of mutation when auth is disabled and request has no voter-id
+const AnonymousActor = "anonymous"
+
+// One mutation of crypto, who did it and the fields changed
+type AuditEvent struct {
+	Id        primitive.ObjectID `json:"id" bson:"_id"`
+	CryptoId  primitive.ObjectID `json:"crypto_id" bson:"crypto_id"`
+	Actor     string             `json:"actor" bson:"actor"`
+	RpcMethod string             `json:"rpc_method" bson:"rpc_method"`
+	Time      time.Time          `json:"time" bson:"time"`
+	Changes   []AuditChange      `json:"changes" bson:"changes"`
+}
+
+// Value of field before and after mutation, empty when the crypto did not exist
+type AuditChange struct {
+	Field  string `json:"field" bson:"field"`
+	Before string `json:"before" bson:"before"`
+	After  string `json:"after" bson:"after"`
+}
+
+// Diff returns the fields changed from before to after, nil before is a create and nil after is a delete
+func Diff(before *CryptoCurrency, after *CryptoCurrency) []AuditChange {
+	changes := []AuditChange{}
+
+	for _, field := range []string{"name", "asset_id", "price_usd", "votes", "deleted_at"} {
+		change := AuditChange{Field: field}
+		if before != nil {
+			change.Before = before.fieldValue(field)
+		}
+		if after != nil {
+			change.After = after.fieldValue(field)
+		}
+
+		if change.Before != change.After {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+func (c *CryptoCurrency) fieldValue(field string) string {
+	switch field {
+	case "name":
+		return c.Name
+	case "asset_id":
+		return c.AssetId
+	case "price_usd":
+		return strconv.FormatFloat(c.PriceUsd, 'f', -1, 64)
+	case "votes":
+		return strconv.Itoa(int(c.Votes))
+	case "deleted_at":
+		if c.DeletedAt == nil {
+			return ""
+		}
+		return c.DeletedAt.UTC().Format("2006-01-02T15:04:05.999Z")
+	}
+	return ""
+}
+
+func (e *AuditEvent) ToProtoAuditEvent() *proto.AuditEvent {
+	event := &proto.AuditEvent{
+		Id:        e.Id.Hex(),
+		CryptoId:  e.CryptoId.Hex(),
+		Actor:     e.Actor,
+		RpcMethod: e.RpcMethod,
+		Time:      e.Time.UTC().Format("2006-01-02T15:04:05.999Z"),
+	}
+
+	for _, change := range e.Changes {
+		event.Changes = append(event.Changes, &proto.AuditChange{
+			Field:  change.Field,
+			Before: change.Before,
+			After:  change.After,
+		})
+	}
+
+	return event
+}
@@ -9,9 +9,8 @@ import (
 )
 
 const (
-	UpdateOnly = "UPDATE"
-	UpVote     = "UPVOTE"
-	DownVote   = "DOWNVOTE"
+	UpdateOnly  = "UPDATE"
+	UpdateVotes = "VOTES"
 )
 
 type CryptoCurrencies struct {
@@ -19,14 +18,19 @@ type CryptoCurrencies struct {
 }
 
 type CryptoCurrency struct {
-	Id         primitive.ObjectID `json:"id" bson:"_id"`
-	Name       string             `json:"name" bson:"name"`
-	AssetId    string             `json:"asset_id" bson:"asset_id"`
-	PriceUsd   float64            `json:"price_usd" bson:"price_usd"`
-	Votes      int32              `json:"votes" bson:"votes"`
-	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt  time.Time          `json:"updated_at" bson:"updated_at"`
-	UpdateType string             `json:"-" bson:"-"` // Not insert in db
+	Id              primitive.ObjectID `json:"id" bson:"_id"`
+	Name            string             `json:"name" bson:"name"`
+	AssetId         string             `json:"asset_id" bson:"asset_id"`
+	PriceUsd        float64            `json:"price_usd" bson:"price_usd"`
+	Votes           int32              `json:"votes" bson:"votes"`
+	CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at" bson:"updated_at"`
+	DeletedAt       *time.Time         `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"` // Set by soft delete
+	Deleted         bool               `json:"-" bson:"deleted"`                                 // Same of DeletedAt != nil, filter of index asset_id_unique
+	Version         int64              `json:"version" bson:"version"`                           // Incremented by each update
+	UpdateType      string             `json:"-" bson:"-"`                                       // Not insert in db
+	VotesDelta      int32              `json:"-" bson:"-"`                                       // Not insert in db, used by UpdateVotes
+	ExpectedVersion *int64             `json:"-" bson:"-"`                                       // Not insert in db, used by UpdateOnly, nil updates any version
 }
 
 func (c *CryptoCurrency) ToProtoCrypto() proto.CryptoCurrency {
@@ -38,6 +42,7 @@ func (c *CryptoCurrency) ToProtoCrypto() proto.CryptoCurrency {
 		Votes:     int32(c.Votes),
 		CreatedAt: c.CreatedAt.Format("2006-01-02T15:04:05.999Z"),
 		UpdatedAt: c.UpdatedAt.Format("2006-01-02T15:04:05.999Z"),
+		Version:   c.Version,
 	}
 }
 
@@ -45,12 +50,14 @@ func (c *CryptoCurrency) PrepateToInsert() {
 	c.Id = primitive.NewObjectID()
 	c.CreatedAt = time.Now()
 	c.UpdatedAt = time.Now()
+	c.Version = 1
 }
 
 func (c *CryptoCurrency) RevertPrepateToInsert() {
 	c.Id = primitive.NilObjectID
 	c.CreatedAt = time.Time{}
 	c.UpdatedAt = time.Time{}
+	c.Version = 0
 }
 
 func (c CryptoCurrency) FieldsToUpdate() bson.M {
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"api-desafio-kvr/proto"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Price of crypto since Time, a new point is saved only when the price changes
+type PricePoint struct {
+	Id       primitive.ObjectID `json:"id" bson:"_id"`
+	CryptoId primitive.ObjectID `json:"crypto_id" bson:"crypto_id"`
+	PriceUsd float64            `json:"price_usd" bson:"price_usd"`
+	Time     time.Time          `json:"time" bson:"time"`
+}
+
+// Prices of one interval, in raw each point is a bucket with Count 1
+type PriceBucket struct {
+	Start time.Time `json:"start" bson:"_id"`
+	Open  float64   `json:"open" bson:"open"`
+	High  float64   `json:"high" bson:"high"`
+	Low   float64   `json:"low" bson:"low"`
+	Close float64   `json:"close" bson:"close"`
+	Count int32     `json:"count" bson:"count"`
+}
+
+func (p PricePoint) ToBucket() PriceBucket {
+	return PriceBucket{
+		Start: p.Time,
+		Open:  p.PriceUsd,
+		High:  p.PriceUsd,
+		Low:   p.PriceUsd,
+		Close: p.PriceUsd,
+		Count: 1,
+	}
+}
+
+func (b *PriceBucket) ToProtoBucket() *proto.PriceBucket {
+	return &proto.PriceBucket{
+		Start: b.Start.UTC().Format("2006-01-02T15:04:05.999Z"),
+		Open:  b.Open,
+		High:  b.High,
+		Low:   b.Low,
+		Close: b.Close,
+		Count: b.Count,
+	}
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Current vote of voter in crypto, the votes of crypto is the sum of values
+const (
+	VoteNone int32 = 0
+	VoteUp   int32 = 1
+	VoteDown int32 = -1
+)
+
+type Vote struct {
+	Id        primitive.ObjectID `json:"id" bson:"_id"`
+	VoterId   string             `json:"voter_id" bson:"voter_id"`
+	CryptoId  primitive.ObjectID `json:"crypto_id" bson:"crypto_id"`
+	Value     int32              `json:"value" bson:"value"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
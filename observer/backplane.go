@@ -0,0 +1,158 @@
+package observer
+
+import (
+	"api-desafio-kvr/helpers"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var logger = &helpers.Log{}
+
+const (
+	BackplaneLocal = "local"
+	BackplaneRedis = "redis"
+)
+
+// Events kept to replay
+const DefaultLogSize = 1000
+
+// Backplane delivers the events to the hub of each replica and numbers them in order, local only to this replica
+type Backplane interface {
+	// Publish sends the event to the hubs, changes with same key are sent once by all replicas, empty key is always sent
+	Publish(ctx context.Context, key string, event Event) error
+	// Since returns the events of log after sequence, in order. gap is true when events after sequence
+	// are not in log anymore: evicted or lost, as the sequence of a local backplane after a restart
+	Since(ctx context.Context, sequence int64) (events []Event, gap bool, err error)
+	Close() error
+}
+
+type Config struct {
+	Backplane string `json:"backplane"` // local or redis
+	Channel   string `json:"channel"`   // channel of redis
+	LogSize   int    `json:"log_size"`  // last events kept to replay
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Backplane: BackplaneLocal,
+		Channel:   DefaultChannel,
+		LogSize:   DefaultLogSize,
+	}
+}
+
+// LoadConfig reads section "stream" of CONFIG_FILE and the env vars STREAM_*
+func LoadConfig() (Config, error) {
+	cfg := DefaultConfig()
+
+	err := helpers.LoadConfigFile("stream", &cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	envs := map[string]interface{}{
+		"STREAM_BACKPLANE": &cfg.Backplane,
+		"STREAM_CHANNEL":   &cfg.Channel,
+		"STREAM_LOG_SIZE":  &cfg.LogSize,
+	}
+	for key, target := range envs {
+		err = helpers.SetFromEnv(key, target)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	if cfg.Backplane != BackplaneLocal && cfg.Backplane != BackplaneRedis {
+		return cfg, errors.New("stream backplane is invalid: " + cfg.Backplane)
+	}
+
+	if cfg.Channel == "" {
+		return cfg, errors.New("stream channel can not be empty")
+	}
+
+	if cfg.LogSize <= 0 {
+		return cfg, errors.New("stream log size must be positive")
+	}
+
+	return cfg, nil
+}
+
+// Local publishes direct in the hub of this process and the log is the last events in memory.
+// The sequence starts after the epoch of process, its start in microseconds, so the sequences
+// of a process before a restart are lower and their replay has a gap
+type Local struct {
+	hub      *Hub
+	mu       sync.Mutex
+	epoch    int64
+	sequence int64
+	log      []Event
+	logSize  int
+}
+
+var _ Backplane = (*Local)(nil)
+
+func NewLocal(hub *Hub, logSize int) *Local {
+	if logSize <= 0 {
+		logSize = DefaultLogSize
+	}
+	epoch := time.Now().UnixMicro()
+	return &Local{hub: hub, logSize: logSize, epoch: epoch, sequence: epoch}
+}
+
+// Sequence returns the last sequence published, the epoch when there is none
+func (l *Local) Sequence() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.sequence
+}
+
+// Each change is published once in the process, the key is not needed
+func (l *Local) Publish(ctx context.Context, key string, event Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sequence++
+	event.Sequence = l.sequence
+
+	if len(l.log) == l.logSize {
+		l.log = l.log[1:]
+	}
+	l.log = append(l.log, event)
+
+	l.hub.Publish(event)
+	return nil
+}
+
+func (l *Local) Since(ctx context.Context, sequence int64) ([]Event, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// sequence not published yet, the client did not come from this process
+	if sequence > l.sequence {
+		return nil, true, nil
+	}
+
+	// sequence of the process before the restart, its later events were lost. 0 is the start of log
+	from := sequence
+	gap := false
+	if from < l.epoch {
+		gap = from != 0
+		from = l.epoch
+	}
+
+	events := []Event{}
+	for _, event := range l.log {
+		if event.Sequence > from {
+			events = append(events, event)
+		}
+	}
+
+	gap = gap || len(l.log) > 0 && l.log[0].Sequence > from+1
+	return events, gap, nil
+}
+
+func (l *Local) Close() error {
+	return nil
+}
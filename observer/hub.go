@@ -0,0 +1,224 @@
+package observer
+
+import (
+	"api-desafio-kvr/models"
+	"errors"
+	"sync"
+)
+
+// A subscriber whose buffer is full when an event is published is closed with this error, publish never blocks
+var ErrSlowConsumer = errors.New("subscriber is too slow, events were dropped")
+var ErrClosed = errors.New("observer hub is closed")
+
+const DefaultBufferSize = 16
+
+// Id of subscription to the events of all cryptos
+const All = "*"
+
+// Types of event
+const (
+	EventCreated = "CREATED"
+	EventUpdated = "UPDATED"
+	EventVoted   = "VOTED"
+	EventDeleted = "DELETED"
+)
+
+// Event of one crypto, Crypto is the document after the change
+type Event struct {
+	Sequence int64                 `json:"sequence"` // set by backplane, increases in the order of publish
+	Type     string                `json:"type"`
+	Id       string                `json:"id"`
+	Crypto   models.CryptoCurrency `json:"crypto"`
+}
+
+// Hub sends the events of cryptos to the subscribers of their ids or of All, each one with its own buffer
+type Hub struct {
+	mu          sync.Mutex
+	bufferSize  int
+	subscribers map[string]map[*Subscription]struct{}
+	closed      bool
+}
+
+type Subscription struct {
+	ids     []string
+	events  chan Event
+	err     error
+	hub     *Hub
+	held    bool
+	pending []Event
+}
+
+func NewHub(bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+
+	return &Hub{
+		bufferSize:  bufferSize,
+		subscribers: map[string]map[*Subscription]struct{}{},
+	}
+}
+
+// Subscribe registers a new queue for the crypto ids, All receives the events of every crypto
+func (h *Hub) Subscribe(ids ...string) *Subscription {
+	return h.subscribe(false, ids)
+}
+
+// SubscribeHeld is Subscribe with the events kept without limit until Release returns none
+func (h *Hub) SubscribeHeld(ids ...string) *Subscription {
+	return h.subscribe(true, ids)
+}
+
+func (h *Hub) subscribe(held bool, ids []string) *Subscription {
+	sub := &Subscription{
+		ids:    unique(ids),
+		events: make(chan Event, h.bufferSize),
+		hub:    h,
+		held:   held,
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		sub.err = ErrClosed
+		close(sub.events)
+		return sub
+	}
+
+	for _, id := range sub.ids {
+		if h.subscribers[id] == nil {
+			h.subscribers[id] = map[*Subscription]struct{}{}
+		}
+		h.subscribers[id][sub] = struct{}{}
+	}
+
+	return sub
+}
+
+// Publish sends the event to every subscriber of its id and of All without blocking
+func (h *Hub) Publish(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// subscriber of the id and of All receives once
+	targets := map[*Subscription]struct{}{}
+	for sub := range h.subscribers[event.Id] {
+		targets[sub] = struct{}{}
+	}
+	for sub := range h.subscribers[All] {
+		targets[sub] = struct{}{}
+	}
+
+	for sub := range targets {
+		if sub.held {
+			sub.pending = append(sub.pending, event)
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			h.remove(sub, ErrSlowConsumer)
+		}
+	}
+}
+
+// Unsubscribe removes the subscriber and closes its queue
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(sub, nil)
+}
+
+// Close disconnects every subscriber, new subscriptions are closed at once
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, subs := range h.subscribers {
+		for sub := range subs {
+			h.remove(sub, ErrClosed)
+		}
+	}
+}
+
+// Subscribers returns amount of active subscribers to the id
+func (h *Hub) Subscribers(id string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.subscribers[id])
+}
+
+// must be called with h.mu locked
+func (h *Hub) remove(sub *Subscription, reason error) {
+	removed := false
+	for _, id := range sub.ids {
+		subs, ok := h.subscribers[id]
+		if !ok {
+			continue
+		}
+		if _, ok := subs[sub]; !ok {
+			continue
+		}
+
+		removed = true
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(h.subscribers, id)
+		}
+	}
+
+	if !removed {
+		return
+	}
+
+	sub.err = reason
+	close(sub.events)
+}
+
+func unique(ids []string) []string {
+	seen := map[string]bool{}
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// Events returns the queue of subscriber, it is closed when the subscriber is removed
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Err returns reason to the queue was closed, nil if it was by Unsubscribe
+func (s *Subscription) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	return s.err
+}
+
+// Release returns the events kept while held, in the order of publish. When there are none the hold ends
+// and the next events go to the queue, so it is called until it returns none
+func (s *Subscription) Release() []Event {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	pending := s.pending
+	s.pending = nil
+	if len(pending) == 0 {
+		s.held = false
+	}
+	return pending
+}
+
+func (s *Subscription) Unsubscribe() {
+	s.hub.Unsubscribe(s)
+}
@@ -0,0 +1,129 @@
+package observer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Testing every subscriber of same id receives the event
+func TestPublishWithManySubscribers(t *testing.T) {
+	hub := NewHub(1)
+	first := hub.Subscribe("crypto-1")
+	second := hub.Subscribe("crypto-1")
+
+	hub.Publish(Event{Id: "crypto-1"})
+
+	require.Equal(t, "crypto-1", (<-first.Events()).Id)
+	require.Equal(t, "crypto-1", (<-second.Events()).Id)
+}
+
+// Testing subscriber does not receive events of other ids
+func TestPublishWithOtherId(t *testing.T) {
+	hub := NewHub(1)
+	sub := hub.Subscribe("crypto-1")
+
+	hub.Publish(Event{Id: "crypto-2"})
+
+	require.Empty(t, sub.Events())
+	require.Equal(t, 0, hub.Subscribers("crypto-2"))
+}
+
+// Testing slow subscriber is disconnected and others keep receiving
+func TestPublishWithSlowConsumer(t *testing.T) {
+	hub := NewHub(1)
+	slow := hub.Subscribe("crypto-1")
+	fast := hub.Subscribe("crypto-1")
+
+	hub.Publish(Event{Id: "crypto-1"})
+	<-fast.Events()
+	hub.Publish(Event{Id: "crypto-1"})
+
+	<-slow.Events()
+	_, ok := <-slow.Events()
+	require.False(t, ok)
+	require.Equal(t, ErrSlowConsumer, slow.Err())
+
+	require.Equal(t, "crypto-1", (<-fast.Events()).Id)
+	require.Nil(t, fast.Err())
+	require.Equal(t, 1, hub.Subscribers("crypto-1"))
+}
+
+// Testing held subscriber keeps more events than the queue and receives the next ones after release
+func TestSubscribeHeld(t *testing.T) {
+	hub := NewHub(1)
+	sub := hub.SubscribeHeld("crypto-1")
+
+	hub.Publish(Event{Sequence: 1, Id: "crypto-1"})
+	hub.Publish(Event{Sequence: 2, Id: "crypto-1"})
+	hub.Publish(Event{Sequence: 3, Id: "crypto-1"})
+
+	pending := sub.Release()
+	require.Equal(t, 3, len(pending))
+	require.Equal(t, int64(1), pending[0].Sequence)
+	require.Equal(t, int64(3), pending[2].Sequence)
+	require.Empty(t, sub.Events())
+	require.Nil(t, sub.Err())
+
+	hub.Publish(Event{Sequence: 4, Id: "crypto-1"})
+	require.Equal(t, 1, len(sub.Release()))
+
+	require.Empty(t, sub.Release())
+	hub.Publish(Event{Sequence: 5, Id: "crypto-1"})
+	require.Equal(t, int64(5), (<-sub.Events()).Sequence)
+}
+
+// Testing unsubscribe closes queue and removes subscriber
+func TestUnsubscribe(t *testing.T) {
+	hub := NewHub(1)
+	sub := hub.Subscribe("crypto-1")
+
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+
+	_, ok := <-sub.Events()
+	require.False(t, ok)
+	require.Nil(t, sub.Err())
+	require.Equal(t, 0, hub.Subscribers("crypto-1"))
+}
+
+// Testing close disconnects current and future subscribers
+func TestClose(t *testing.T) {
+	hub := NewHub(1)
+	sub := hub.Subscribe("crypto-1")
+
+	hub.Close()
+	late := hub.Subscribe("crypto-1")
+
+	_, ok := <-sub.Events()
+	require.False(t, ok)
+	require.Equal(t, ErrClosed, sub.Err())
+
+	_, ok = <-late.Events()
+	require.False(t, ok)
+	require.Equal(t, ErrClosed, late.Err())
+}
+
+// Testing subscriber of many ids and of all receive each event once
+func TestSubscribeManyIdsAndAll(t *testing.T) {
+	hub := NewHub(4)
+	many := hub.Subscribe("crypto-1", "crypto-2", "crypto-1")
+	all := hub.Subscribe(All)
+
+	hub.Publish(Event{Id: "crypto-1"})
+	hub.Publish(Event{Id: "crypto-3"})
+	hub.Publish(Event{Id: "crypto-2"})
+
+	require.Equal(t, "crypto-1", (<-many.Events()).Id)
+	require.Equal(t, "crypto-2", (<-many.Events()).Id)
+	require.Empty(t, many.Events())
+
+	require.Equal(t, "crypto-1", (<-all.Events()).Id)
+	require.Equal(t, "crypto-3", (<-all.Events()).Id)
+	require.Equal(t, "crypto-2", (<-all.Events()).Id)
+
+	many.Unsubscribe()
+	require.Equal(t, 0, hub.Subscribers("crypto-1"))
+	require.Equal(t, 0, hub.Subscribers("crypto-2"))
+	require.Equal(t, 1, hub.Subscribers(All))
+}
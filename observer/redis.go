@@ -0,0 +1,157 @@
+package observer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+const DefaultChannel = "cryptos:events"
+
+// Keys of changes published are kept so the other replicas see it was sent
+const publishedTTL = time.Hour
+
+// Numbers the event, keeps it in the log and publishes it in one step, so the order in channel and in log
+// is the order of sequence. The entry of log has id sequence-0, the replay reads it by range of sequence.
+// KEYS[1] is the sequence, KEYS[2] the log and KEYS[3], optional, the key of change.
+// Returns 0 when the change was published.
+var publishEvent = redis.NewScript(`
+if KEYS[3] and not redis.call("SET", KEYS[3], 1, "NX", "EX", ARGV[3]) then
+	return 0
+end
+
+local sequence = redis.call("INCR", KEYS[1])
+redis.call("XADD", KEYS[2], "MAXLEN", ARGV[4], sequence .. "-0", "event", ARGV[2])
+redis.call("PUBLISH", ARGV[1], sequence .. ":" .. ARGV[2])
+return sequence
+`)
+
+// Redis publishes the events in a channel of pub/sub, each replica subscribes it and feeds its own hub.
+// The log is a stream of redis with the last events, shared by the replicas
+type Redis struct {
+	rdb     *redis.Client
+	channel string
+	logSize int
+	pubsub  *redis.PubSub
+	hub     *Hub
+	done    chan struct{}
+}
+
+var _ Backplane = (*Redis)(nil)
+
+// NewRedis subscribes the channel, the events published after it returns reach the hub.
+// The pool rdb is of the caller, only the subscription has its own connection.
+func NewRedis(rdb *redis.Client, channel string, logSize int, hub *Hub) (*Redis, error) {
+	if logSize <= 0 {
+		logSize = DefaultLogSize
+	}
+
+	pubsub := rdb.Subscribe(channel)
+
+	// waits the confirmation of subscribe
+	_, err := pubsub.Receive()
+	if err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	r := &Redis{
+		rdb:     rdb,
+		channel: channel,
+		logSize: logSize,
+		pubsub:  pubsub,
+		hub:     hub,
+		done:    make(chan struct{}),
+	}
+	go r.receive()
+
+	return r, nil
+}
+
+// The channel of pubsub reconnects by itself and is closed by Close
+func (r *Redis) receive() {
+	defer close(r.done)
+
+	for message := range r.pubsub.Channel() {
+		event, err := decodeEvent(message.Payload)
+		if err != nil {
+			logger.Error("STREAM", "Event of channel "+r.channel+" is invalid: "+err.Error())
+			continue
+		}
+		r.hub.Publish(event)
+	}
+}
+
+func (r *Redis) Publish(ctx context.Context, key string, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	keys := []string{r.channel + ":sequence", r.channel + ":log"}
+	if key != "" {
+		keys = append(keys, r.channel+":published:"+key)
+	}
+
+	return publishEvent.Run(r.rdb.WithContext(ctx), keys, r.channel, payload, int(publishedTTL.Seconds()), r.logSize).Err()
+}
+
+func (r *Redis) Since(ctx context.Context, sequence int64) ([]Event, bool, error) {
+	rdb := r.rdb.WithContext(ctx)
+
+	entries, err := rdb.XRange(r.channel+":log", strconv.FormatInt(sequence+1, 10)+"-0", "+").Result()
+	if err != nil {
+		return nil, false, err
+	}
+
+	events := make([]Event, 0, len(entries))
+	for _, entry := range entries {
+		payload, _ := entry.Values["event"].(string)
+		event, err := decodeEvent(strings.TrimSuffix(entry.ID, "-0") + ":" + payload)
+		if err != nil {
+			return nil, false, err
+		}
+		events = append(events, event)
+	}
+
+	if len(events) > 0 {
+		return events, events[0].Sequence > sequence+1, nil
+	}
+
+	// the log keeps the last event, empty is the end of log or a sequence that redis does not have anymore
+	last, err := rdb.Get(r.channel + ":sequence").Int64()
+	if err != nil && err != redis.Nil {
+		return nil, false, err
+	}
+	return events, sequence > last, nil
+}
+
+// Message is sequence:json of event
+func decodeEvent(payload string) (Event, error) {
+	var event Event
+
+	i := strings.Index(payload, ":")
+	if i < 0 {
+		return event, errors.New("sequence not found")
+	}
+
+	err := json.Unmarshal([]byte(payload[i+1:]), &event)
+	if err != nil {
+		return event, err
+	}
+
+	event.Sequence, err = strconv.ParseInt(payload[:i], 10, 64)
+	return event, err
+}
+
+// Close ends the subscription, the pool is not closed
+func (r *Redis) Close() error {
+	err := r.pubsub.Close()
+	<-r.done
+	return err
+}
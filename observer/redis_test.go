@@ -0,0 +1,196 @@
+package observer
+
+import (
+	"api-desafio-kvr/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Help function to start one replica: its hub subscribed to channel of server
+func startReplica(t *testing.T, server *miniredis.Miniredis) (*Hub, *Redis) {
+	hub := NewHub(4)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	backplane, err := NewRedis(rdb, DefaultChannel, 3, hub)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		backplane.Close()
+		rdb.Close()
+	})
+	return hub, backplane
+}
+
+func receive(t *testing.T, sub *Subscription) Event {
+	select {
+	case event := <-sub.Events():
+		return event
+	case <-time.After(3 * time.Second):
+		t.Fatal("event not received")
+		return Event{}
+	}
+}
+
+// Testing the event published by one replica reaches the streams of all replicas, with same sequence
+func TestRedisWithManyReplicas(t *testing.T) {
+	server := miniredis.RunT(t)
+	hubA, backplaneA := startReplica(t, server)
+	hubB, _ := startReplica(t, server)
+
+	subA := hubA.Subscribe("crypto-1")
+	subB := hubB.Subscribe(All)
+
+	crypto := models.CryptoCurrency{Id: primitive.NewObjectID(), Name: "Bitcoin", Votes: 3}
+	require.Nil(t, backplaneA.Publish(context.Background(), "", Event{Type: EventVoted, Id: "crypto-1", Crypto: crypto}))
+
+	eventA := receive(t, subA)
+	require.Equal(t, int64(1), eventA.Sequence)
+	require.Equal(t, EventVoted, eventA.Type)
+	require.Equal(t, crypto.Id, eventA.Crypto.Id)
+	require.Equal(t, int32(3), eventA.Crypto.Votes)
+	require.Equal(t, eventA, receive(t, subB))
+}
+
+// Testing a change published by every replica with same key reaches the streams once
+func TestRedisWithSameKey(t *testing.T) {
+	server := miniredis.RunT(t)
+	hubA, backplaneA := startReplica(t, server)
+	hubB, backplaneB := startReplica(t, server)
+
+	subA := hubA.Subscribe("crypto-1")
+	subB := hubB.Subscribe("crypto-1")
+
+	require.Nil(t, backplaneA.Publish(context.Background(), "8263A1", Event{Id: "crypto-1"}))
+	require.Nil(t, backplaneB.Publish(context.Background(), "8263A1", Event{Id: "crypto-1"}))
+	require.Nil(t, backplaneB.Publish(context.Background(), "8263A2", Event{Id: "crypto-1"}))
+
+	require.Equal(t, int64(1), receive(t, subA).Sequence)
+	require.Equal(t, int64(2), receive(t, subA).Sequence)
+	require.Equal(t, int64(1), receive(t, subB).Sequence)
+	require.Equal(t, int64(2), receive(t, subB).Sequence)
+
+	time.Sleep(50 * time.Millisecond)
+	require.Empty(t, subA.Events())
+	require.Empty(t, subB.Events())
+	require.True(t, server.Exists(DefaultChannel+":published:8263A1"))
+}
+
+// Testing backplane local publishes in the hub, numbered in order
+func TestLocal(t *testing.T) {
+	hub := NewHub(2)
+	sub := hub.Subscribe("crypto-1")
+	local := NewLocal(hub, 3)
+	epoch := local.Sequence()
+
+	require.Nil(t, local.Publish(context.Background(), "8263A1", Event{Id: "crypto-1"}))
+	require.Nil(t, local.Publish(context.Background(), "", Event{Id: "crypto-1"}))
+	require.Equal(t, epoch+1, (<-sub.Events()).Sequence)
+	require.Equal(t, epoch+2, (<-sub.Events()).Sequence)
+}
+
+// Testing backplane of config is local or redis
+func TestLoadConfig(t *testing.T) {
+	cfg, err := LoadConfig()
+	require.Nil(t, err)
+	require.Equal(t, BackplaneLocal, cfg.Backplane)
+
+	t.Setenv("STREAM_BACKPLANE", "kafka")
+	_, err = LoadConfig()
+	require.NotNil(t, err)
+}
+
+// Help function to test the log of both backplanes, with size 3, base is the sequence before the first event
+func testLog(t *testing.T, backplane Backplane, base int64) {
+	ctx := context.Background()
+
+	events, gap, err := backplane.Since(ctx, 0)
+	require.Nil(t, err)
+	require.False(t, gap)
+	require.Empty(t, events)
+
+	for i := 0; i < 5; i++ {
+		require.Nil(t, backplane.Publish(ctx, "", Event{Type: EventVoted, Id: "crypto-1"}))
+	}
+
+	sequences := func(events []Event) []int64 {
+		result := []int64{}
+		for _, event := range events {
+			result = append(result, event.Sequence-base)
+		}
+		return result
+	}
+
+	// 1 and 2 were evicted
+	events, gap, err = backplane.Since(ctx, 0)
+	require.Nil(t, err)
+	require.True(t, gap)
+	require.Equal(t, []int64{3, 4, 5}, sequences(events))
+	require.Equal(t, EventVoted, events[0].Type)
+
+	events, gap, err = backplane.Since(ctx, base+2)
+	require.Nil(t, err)
+	require.False(t, gap)
+	require.Equal(t, []int64{3, 4, 5}, sequences(events))
+
+	events, gap, err = backplane.Since(ctx, base+4)
+	require.Nil(t, err)
+	require.False(t, gap)
+	require.Equal(t, []int64{5}, sequences(events))
+
+	events, gap, err = backplane.Since(ctx, base+5)
+	require.Nil(t, err)
+	require.False(t, gap)
+	require.Empty(t, events)
+
+	// sequence not known, as after a restart
+	events, gap, err = backplane.Since(ctx, base+9)
+	require.Nil(t, err)
+	require.True(t, gap)
+	require.Empty(t, events)
+}
+
+// Testing log of local backplane keeps the last events
+func TestLocalLog(t *testing.T) {
+	local := NewLocal(NewHub(1), 3)
+	testLog(t, local, local.Sequence())
+}
+
+// Testing the replay of a sequence of the process before a restart has a gap
+func TestLocalLogAfterRestart(t *testing.T) {
+	ctx := context.Background()
+
+	before := NewLocal(NewHub(1), 3)
+	require.Nil(t, before.Publish(ctx, "", Event{Type: EventVoted, Id: "crypto-1"}))
+	require.Nil(t, before.Publish(ctx, "", Event{Type: EventVoted, Id: "crypto-1"}))
+	lastReceived := before.Sequence()
+
+	time.Sleep(time.Millisecond)
+	after := NewLocal(NewHub(1), 3)
+	require.Greater(t, after.Sequence(), lastReceived)
+	require.Nil(t, after.Publish(ctx, "", Event{Type: EventVoted, Id: "crypto-1"}))
+
+	events, gap, err := after.Since(ctx, lastReceived)
+	require.Nil(t, err)
+	require.True(t, gap)
+	require.Len(t, events, 1)
+	require.Equal(t, after.Sequence(), events[0].Sequence)
+
+	// 0 is the start of log, nothing of this process was evicted
+	events, gap, err = after.Since(ctx, 0)
+	require.Nil(t, err)
+	require.False(t, gap)
+	require.Len(t, events, 1)
+}
+
+// Testing log of redis backplane keeps the last events in a stream
+func TestRedisLog(t *testing.T) {
+	server := miniredis.RunT(t)
+	_, backplane := startReplica(t, server)
+
+	testLog(t, backplane, 0)
+	require.True(t, server.Exists(DefaultChannel+":log"))
+}
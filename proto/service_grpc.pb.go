@@ -25,11 +25,20 @@ type EndPointCryptosClient interface {
 	CreateCrypto(ctx context.Context, in *CreateCryptoReq, opts ...grpc.CallOption) (*CryptoCurrency, error)
 	EditCrypto(ctx context.Context, in *EditCryptoReq, opts ...grpc.CallOption) (*CryptoCurrency, error)
 	DeleteCrypo(ctx context.Context, in *DeleteCryptoReq, opts ...grpc.CallOption) (*DefaultResp, error)
+	RestoreCrypto(ctx context.Context, in *RestoreCryptoReq, opts ...grpc.CallOption) (*CryptoCurrency, error)
+	PurgeDeleted(ctx context.Context, in *PurgeDeletedReq, opts ...grpc.CallOption) (*PurgeDeletedResp, error)
 	FindCrypto(ctx context.Context, in *FindCryptoReq, opts ...grpc.CallOption) (*CryptoCurrency, error)
+	FindCryptoByAsset(ctx context.Context, in *FindCryptoByAssetReq, opts ...grpc.CallOption) (*CryptoCurrency, error)
 	ListAllCryptos(ctx context.Context, in *SortCryptosReq, opts ...grpc.CallOption) (*ListCryptosResp, error)
+	SearchCryptos(ctx context.Context, in *SearchCryptosReq, opts ...grpc.CallOption) (*ListCryptosResp, error)
 	Upvote(ctx context.Context, in *VoteReq, opts ...grpc.CallOption) (*DefaultResp, error)
 	Downvote(ctx context.Context, in *VoteReq, opts ...grpc.CallOption) (*DefaultResp, error)
+	RemoveVote(ctx context.Context, in *VoteReq, opts ...grpc.CallOption) (*DefaultResp, error)
 	MonitorVotes(ctx context.Context, in *MonitorVotesReq, opts ...grpc.CallOption) (EndPointCryptos_MonitorVotesClient, error)
+	GetPriceHistory(ctx context.Context, in *PriceHistoryReq, opts ...grpc.CallOption) (*PriceHistoryResp, error)
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsReq, opts ...grpc.CallOption) (*ListAuditEventsResp, error)
+	WatchCryptos(ctx context.Context, in *WatchCryptosReq, opts ...grpc.CallOption) (EndPointCryptos_WatchCryptosClient, error)
+	MonitorVotesEvents(ctx context.Context, in *MonitorVotesReq, opts ...grpc.CallOption) (EndPointCryptos_MonitorVotesEventsClient, error)
 }
 
 type endPointCryptosClient struct {
@@ -67,6 +76,24 @@ func (c *endPointCryptosClient) DeleteCrypo(ctx context.Context, in *DeleteCrypt
 	return out, nil
 }
 
+func (c *endPointCryptosClient) RestoreCrypto(ctx context.Context, in *RestoreCryptoReq, opts ...grpc.CallOption) (*CryptoCurrency, error) {
+	out := new(CryptoCurrency)
+	err := c.cc.Invoke(ctx, "/proto.EndPointCryptos/RestoreCrypto", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *endPointCryptosClient) PurgeDeleted(ctx context.Context, in *PurgeDeletedReq, opts ...grpc.CallOption) (*PurgeDeletedResp, error) {
+	out := new(PurgeDeletedResp)
+	err := c.cc.Invoke(ctx, "/proto.EndPointCryptos/PurgeDeleted", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *endPointCryptosClient) FindCrypto(ctx context.Context, in *FindCryptoReq, opts ...grpc.CallOption) (*CryptoCurrency, error) {
 	out := new(CryptoCurrency)
 	err := c.cc.Invoke(ctx, "/proto.EndPointCryptos/FindCrypto", in, out, opts...)
@@ -76,6 +103,15 @@ func (c *endPointCryptosClient) FindCrypto(ctx context.Context, in *FindCryptoRe
 	return out, nil
 }
 
+func (c *endPointCryptosClient) FindCryptoByAsset(ctx context.Context, in *FindCryptoByAssetReq, opts ...grpc.CallOption) (*CryptoCurrency, error) {
+	out := new(CryptoCurrency)
+	err := c.cc.Invoke(ctx, "/proto.EndPointCryptos/FindCryptoByAsset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *endPointCryptosClient) ListAllCryptos(ctx context.Context, in *SortCryptosReq, opts ...grpc.CallOption) (*ListCryptosResp, error) {
 	out := new(ListCryptosResp)
 	err := c.cc.Invoke(ctx, "/proto.EndPointCryptos/ListAllCryptos", in, out, opts...)
@@ -85,6 +121,15 @@ func (c *endPointCryptosClient) ListAllCryptos(ctx context.Context, in *SortCryp
 	return out, nil
 }
 
+func (c *endPointCryptosClient) SearchCryptos(ctx context.Context, in *SearchCryptosReq, opts ...grpc.CallOption) (*ListCryptosResp, error) {
+	out := new(ListCryptosResp)
+	err := c.cc.Invoke(ctx, "/proto.EndPointCryptos/SearchCryptos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *endPointCryptosClient) Upvote(ctx context.Context, in *VoteReq, opts ...grpc.CallOption) (*DefaultResp, error) {
 	out := new(DefaultResp)
 	err := c.cc.Invoke(ctx, "/proto.EndPointCryptos/Upvote", in, out, opts...)
@@ -103,6 +148,15 @@ func (c *endPointCryptosClient) Downvote(ctx context.Context, in *VoteReq, opts
 	return out, nil
 }
 
+func (c *endPointCryptosClient) RemoveVote(ctx context.Context, in *VoteReq, opts ...grpc.CallOption) (*DefaultResp, error) {
+	out := new(DefaultResp)
+	err := c.cc.Invoke(ctx, "/proto.EndPointCryptos/RemoveVote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *endPointCryptosClient) MonitorVotes(ctx context.Context, in *MonitorVotesReq, opts ...grpc.CallOption) (EndPointCryptos_MonitorVotesClient, error) {
 	stream, err := c.cc.NewStream(ctx, &EndPointCryptos_ServiceDesc.Streams[0], "/proto.EndPointCryptos/MonitorVotes", opts...)
 	if err != nil {
@@ -135,6 +189,88 @@ func (x *endPointCryptosMonitorVotesClient) Recv() (*CryptoCurrency, error) {
 	return m, nil
 }
 
+func (c *endPointCryptosClient) GetPriceHistory(ctx context.Context, in *PriceHistoryReq, opts ...grpc.CallOption) (*PriceHistoryResp, error) {
+	out := new(PriceHistoryResp)
+	err := c.cc.Invoke(ctx, "/proto.EndPointCryptos/GetPriceHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *endPointCryptosClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsReq, opts ...grpc.CallOption) (*ListAuditEventsResp, error) {
+	out := new(ListAuditEventsResp)
+	err := c.cc.Invoke(ctx, "/proto.EndPointCryptos/ListAuditEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *endPointCryptosClient) WatchCryptos(ctx context.Context, in *WatchCryptosReq, opts ...grpc.CallOption) (EndPointCryptos_WatchCryptosClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EndPointCryptos_ServiceDesc.Streams[1], "/proto.EndPointCryptos/WatchCryptos", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &endPointCryptosWatchCryptosClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EndPointCryptos_WatchCryptosClient interface {
+	Recv() (*CryptoEvent, error)
+	grpc.ClientStream
+}
+
+type endPointCryptosWatchCryptosClient struct {
+	grpc.ClientStream
+}
+
+func (x *endPointCryptosWatchCryptosClient) Recv() (*CryptoEvent, error) {
+	m := new(CryptoEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *endPointCryptosClient) MonitorVotesEvents(ctx context.Context, in *MonitorVotesReq, opts ...grpc.CallOption) (EndPointCryptos_MonitorVotesEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EndPointCryptos_ServiceDesc.Streams[2], "/proto.EndPointCryptos/MonitorVotesEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &endPointCryptosMonitorVotesEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EndPointCryptos_MonitorVotesEventsClient interface {
+	Recv() (*CryptoEvent, error)
+	grpc.ClientStream
+}
+
+type endPointCryptosMonitorVotesEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *endPointCryptosMonitorVotesEventsClient) Recv() (*CryptoEvent, error) {
+	m := new(CryptoEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // EndPointCryptosServer is the server API for EndPointCryptos service.
 // All implementations must embed UnimplementedEndPointCryptosServer
 // for forward compatibility
@@ -142,11 +278,20 @@ type EndPointCryptosServer interface {
 	CreateCrypto(context.Context, *CreateCryptoReq) (*CryptoCurrency, error)
 	EditCrypto(context.Context, *EditCryptoReq) (*CryptoCurrency, error)
 	DeleteCrypo(context.Context, *DeleteCryptoReq) (*DefaultResp, error)
+	RestoreCrypto(context.Context, *RestoreCryptoReq) (*CryptoCurrency, error)
+	PurgeDeleted(context.Context, *PurgeDeletedReq) (*PurgeDeletedResp, error)
 	FindCrypto(context.Context, *FindCryptoReq) (*CryptoCurrency, error)
+	FindCryptoByAsset(context.Context, *FindCryptoByAssetReq) (*CryptoCurrency, error)
 	ListAllCryptos(context.Context, *SortCryptosReq) (*ListCryptosResp, error)
+	SearchCryptos(context.Context, *SearchCryptosReq) (*ListCryptosResp, error)
 	Upvote(context.Context, *VoteReq) (*DefaultResp, error)
 	Downvote(context.Context, *VoteReq) (*DefaultResp, error)
+	RemoveVote(context.Context, *VoteReq) (*DefaultResp, error)
 	MonitorVotes(*MonitorVotesReq, EndPointCryptos_MonitorVotesServer) error
+	GetPriceHistory(context.Context, *PriceHistoryReq) (*PriceHistoryResp, error)
+	ListAuditEvents(context.Context, *ListAuditEventsReq) (*ListAuditEventsResp, error)
+	WatchCryptos(*WatchCryptosReq, EndPointCryptos_WatchCryptosServer) error
+	MonitorVotesEvents(*MonitorVotesReq, EndPointCryptos_MonitorVotesEventsServer) error
 	mustEmbedUnimplementedEndPointCryptosServer()
 }
 
@@ -163,21 +308,48 @@ func (UnimplementedEndPointCryptosServer) EditCrypto(context.Context, *EditCrypt
 func (UnimplementedEndPointCryptosServer) DeleteCrypo(context.Context, *DeleteCryptoReq) (*DefaultResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteCrypo not implemented")
 }
+func (UnimplementedEndPointCryptosServer) RestoreCrypto(context.Context, *RestoreCryptoReq) (*CryptoCurrency, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreCrypto not implemented")
+}
+func (UnimplementedEndPointCryptosServer) PurgeDeleted(context.Context, *PurgeDeletedReq) (*PurgeDeletedResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeDeleted not implemented")
+}
 func (UnimplementedEndPointCryptosServer) FindCrypto(context.Context, *FindCryptoReq) (*CryptoCurrency, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FindCrypto not implemented")
 }
+func (UnimplementedEndPointCryptosServer) FindCryptoByAsset(context.Context, *FindCryptoByAssetReq) (*CryptoCurrency, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindCryptoByAsset not implemented")
+}
 func (UnimplementedEndPointCryptosServer) ListAllCryptos(context.Context, *SortCryptosReq) (*ListCryptosResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListAllCryptos not implemented")
 }
+func (UnimplementedEndPointCryptosServer) SearchCryptos(context.Context, *SearchCryptosReq) (*ListCryptosResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchCryptos not implemented")
+}
 func (UnimplementedEndPointCryptosServer) Upvote(context.Context, *VoteReq) (*DefaultResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Upvote not implemented")
 }
 func (UnimplementedEndPointCryptosServer) Downvote(context.Context, *VoteReq) (*DefaultResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Downvote not implemented")
 }
+func (UnimplementedEndPointCryptosServer) RemoveVote(context.Context, *VoteReq) (*DefaultResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveVote not implemented")
+}
 func (UnimplementedEndPointCryptosServer) MonitorVotes(*MonitorVotesReq, EndPointCryptos_MonitorVotesServer) error {
 	return status.Errorf(codes.Unimplemented, "method MonitorVotes not implemented")
 }
+func (UnimplementedEndPointCryptosServer) GetPriceHistory(context.Context, *PriceHistoryReq) (*PriceHistoryResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPriceHistory not implemented")
+}
+func (UnimplementedEndPointCryptosServer) ListAuditEvents(context.Context, *ListAuditEventsReq) (*ListAuditEventsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
+func (UnimplementedEndPointCryptosServer) WatchCryptos(*WatchCryptosReq, EndPointCryptos_WatchCryptosServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchCryptos not implemented")
+}
+func (UnimplementedEndPointCryptosServer) MonitorVotesEvents(*MonitorVotesReq, EndPointCryptos_MonitorVotesEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method MonitorVotesEvents not implemented")
+}
 func (UnimplementedEndPointCryptosServer) mustEmbedUnimplementedEndPointCryptosServer() {}
 
 // UnsafeEndPointCryptosServer may be embedded to opt out of forward compatibility for this service.
@@ -245,6 +417,42 @@ func _EndPointCryptos_DeleteCrypo_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EndPointCryptos_RestoreCrypto_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreCryptoReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EndPointCryptosServer).RestoreCrypto(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.EndPointCryptos/RestoreCrypto",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EndPointCryptosServer).RestoreCrypto(ctx, req.(*RestoreCryptoReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EndPointCryptos_PurgeDeleted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeDeletedReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EndPointCryptosServer).PurgeDeleted(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.EndPointCryptos/PurgeDeleted",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EndPointCryptosServer).PurgeDeleted(ctx, req.(*PurgeDeletedReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _EndPointCryptos_FindCrypto_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FindCryptoReq)
 	if err := dec(in); err != nil {
@@ -263,6 +471,24 @@ func _EndPointCryptos_FindCrypto_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EndPointCryptos_FindCryptoByAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindCryptoByAssetReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EndPointCryptosServer).FindCryptoByAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.EndPointCryptos/FindCryptoByAsset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EndPointCryptosServer).FindCryptoByAsset(ctx, req.(*FindCryptoByAssetReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _EndPointCryptos_ListAllCryptos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SortCryptosReq)
 	if err := dec(in); err != nil {
@@ -281,6 +507,24 @@ func _EndPointCryptos_ListAllCryptos_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EndPointCryptos_SearchCryptos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchCryptosReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EndPointCryptosServer).SearchCryptos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.EndPointCryptos/SearchCryptos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EndPointCryptosServer).SearchCryptos(ctx, req.(*SearchCryptosReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _EndPointCryptos_Upvote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(VoteReq)
 	if err := dec(in); err != nil {
@@ -317,6 +561,24 @@ func _EndPointCryptos_Downvote_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EndPointCryptos_RemoveVote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoteReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EndPointCryptosServer).RemoveVote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.EndPointCryptos/RemoveVote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EndPointCryptosServer).RemoveVote(ctx, req.(*VoteReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _EndPointCryptos_MonitorVotes_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(MonitorVotesReq)
 	if err := stream.RecvMsg(m); err != nil {
@@ -338,6 +600,84 @@ func (x *endPointCryptosMonitorVotesServer) Send(m *CryptoCurrency) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _EndPointCryptos_GetPriceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PriceHistoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EndPointCryptosServer).GetPriceHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.EndPointCryptos/GetPriceHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EndPointCryptosServer).GetPriceHistory(ctx, req.(*PriceHistoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EndPointCryptos_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EndPointCryptosServer).ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.EndPointCryptos/ListAuditEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EndPointCryptosServer).ListAuditEvents(ctx, req.(*ListAuditEventsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EndPointCryptos_WatchCryptos_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCryptosReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EndPointCryptosServer).WatchCryptos(m, &endPointCryptosWatchCryptosServer{stream})
+}
+
+type EndPointCryptos_WatchCryptosServer interface {
+	Send(*CryptoEvent) error
+	grpc.ServerStream
+}
+
+type endPointCryptosWatchCryptosServer struct {
+	grpc.ServerStream
+}
+
+func (x *endPointCryptosWatchCryptosServer) Send(m *CryptoEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _EndPointCryptos_MonitorVotesEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MonitorVotesReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EndPointCryptosServer).MonitorVotesEvents(m, &endPointCryptosMonitorVotesEventsServer{stream})
+}
+
+type EndPointCryptos_MonitorVotesEventsServer interface {
+	Send(*CryptoEvent) error
+	grpc.ServerStream
+}
+
+type endPointCryptosMonitorVotesEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *endPointCryptosMonitorVotesEventsServer) Send(m *CryptoEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // EndPointCryptos_ServiceDesc is the grpc.ServiceDesc for EndPointCryptos service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -357,14 +697,30 @@ var EndPointCryptos_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteCrypo",
 			Handler:    _EndPointCryptos_DeleteCrypo_Handler,
 		},
+		{
+			MethodName: "RestoreCrypto",
+			Handler:    _EndPointCryptos_RestoreCrypto_Handler,
+		},
+		{
+			MethodName: "PurgeDeleted",
+			Handler:    _EndPointCryptos_PurgeDeleted_Handler,
+		},
 		{
 			MethodName: "FindCrypto",
 			Handler:    _EndPointCryptos_FindCrypto_Handler,
 		},
+		{
+			MethodName: "FindCryptoByAsset",
+			Handler:    _EndPointCryptos_FindCryptoByAsset_Handler,
+		},
 		{
 			MethodName: "ListAllCryptos",
 			Handler:    _EndPointCryptos_ListAllCryptos_Handler,
 		},
+		{
+			MethodName: "SearchCryptos",
+			Handler:    _EndPointCryptos_SearchCryptos_Handler,
+		},
 		{
 			MethodName: "Upvote",
 			Handler:    _EndPointCryptos_Upvote_Handler,
@@ -373,6 +729,18 @@ var EndPointCryptos_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Downvote",
 			Handler:    _EndPointCryptos_Downvote_Handler,
 		},
+		{
+			MethodName: "RemoveVote",
+			Handler:    _EndPointCryptos_RemoveVote_Handler,
+		},
+		{
+			MethodName: "GetPriceHistory",
+			Handler:    _EndPointCryptos_GetPriceHistory_Handler,
+		},
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _EndPointCryptos_ListAuditEvents_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -380,6 +748,16 @@ var EndPointCryptos_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _EndPointCryptos_MonitorVotes_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchCryptos",
+			Handler:       _EndPointCryptos_WatchCryptos_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "MonitorVotesEvents",
+			Handler:       _EndPointCryptos_MonitorVotesEvents_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "proto/service.proto",
 }
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// buckets not used for this time are removed from memory
+const idleBucket = 10 * time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Memory keeps the buckets in process, each replica has its own limits
+type Memory struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+var _ Limiter = (*Memory)(nil)
+
+func NewMemory() *Memory {
+	return &Memory{buckets: map[string]*bucket{}, now: time.Now}
+}
+
+func (m *Memory) Allow(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	if limit.Unlimited() {
+		return true, 0, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens = refill(b.tokens, now.Sub(b.last), limit)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, waitFor(b.tokens, limit), nil
+	}
+
+	b.tokens--
+	return true, 0, nil
+}
+
+// must be called with m.mu locked
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < idleBucket {
+		return
+	}
+	m.lastSweep = now
+
+	for key, b := range m.buckets {
+		if now.Sub(b.last) > idleBucket {
+			delete(m.buckets, key)
+		}
+	}
+}
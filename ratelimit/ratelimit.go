@@ -0,0 +1,143 @@
+package ratelimit
+
+import (
+	"api-desafio-kvr/helpers"
+	"context"
+	"errors"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var logger = &helpers.Log{}
+var nameLog = "RATELIMIT"
+
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis" // limits shared by all replicas
+)
+
+// Limit is a token bucket: Burst calls at once, refilled by Rate calls per second
+type Limit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// Unlimited is a limit with rate or burst zero
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// Limiter takes one token of key, retryAfter is the wait to next token when it is not allowed
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (allowed bool, retryAfter time.Duration, err error)
+}
+
+type Config struct {
+	Enabled bool             `json:"enabled"`
+	Backend string           `json:"backend"` // memory or redis
+	Methods map[string]Limit `json:"methods"` // by name of rpc, as Upvote
+	Default Limit            `json:"default"` // rpcs not in Methods, zero is unlimited
+}
+
+func DefaultConfig() Config {
+	votes := Limit{Rate: 5, Burst: 10}
+	writes := Limit{Rate: 2, Burst: 5}
+
+	return Config{
+		Enabled: true,
+		Backend: BackendMemory,
+		Methods: map[string]Limit{
+			"Upvote":        votes,
+			"Downvote":      votes,
+			"RemoveVote":    votes,
+			"CreateCrypto":  writes,
+			"EditCrypto":    writes,
+			"DeleteCrypo":   writes,
+			"RestoreCrypto": writes,
+			"PurgeDeleted":  writes,
+		},
+	}
+}
+
+// LoadConfig reads section "ratelimit" of CONFIG_FILE and the env vars RATELIMIT_*.
+// RATELIMIT_METHODS is a list "Upvote=5:10,CreateCrypto=2:5" of method=rate:burst.
+func LoadConfig() (Config, error) {
+	cfg := DefaultConfig()
+
+	err := helpers.LoadConfigFile("ratelimit", &cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	envs := map[string]interface{}{
+		"RATELIMIT_ENABLED":       &cfg.Enabled,
+		"RATELIMIT_BACKEND":       &cfg.Backend,
+		"RATELIMIT_DEFAULT_RATE":  &cfg.Default.Rate,
+		"RATELIMIT_DEFAULT_BURST": &cfg.Default.Burst,
+	}
+	for key, target := range envs {
+		err = helpers.SetFromEnv(key, target)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	if value := os.Getenv("RATELIMIT_METHODS"); value != "" {
+		err = parseMethods(value, cfg.Methods)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	if cfg.Backend != BackendMemory && cfg.Backend != BackendRedis {
+		return cfg, errors.New("ratelimit backend is invalid: " + cfg.Backend)
+	}
+
+	return cfg, nil
+}
+
+func parseMethods(value string, methods map[string]Limit) error {
+	for _, entry := range strings.Split(value, ",") {
+		method, limit, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		rate, burst, okLimit := strings.Cut(limit, ":")
+		if !ok || !okLimit || method == "" {
+			return errors.New("env RATELIMIT_METHODS is invalid, use method=rate:burst")
+		}
+
+		r, err := strconv.ParseFloat(rate, 64)
+		if err != nil {
+			return errors.New("rate of " + method + " is invalid: " + rate)
+		}
+		b, err := strconv.Atoi(burst)
+		if err != nil {
+			return errors.New("burst of " + method + " is invalid: " + burst)
+		}
+		methods[method] = Limit{Rate: r, Burst: b}
+	}
+	return nil
+}
+
+// For returns the limit of full method /package.Service/Method
+func (cfg Config) For(fullMethod string) Limit {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	if limit, ok := cfg.Methods[method]; ok {
+		return limit
+	}
+	return cfg.Default
+}
+
+// refill returns the tokens after elapsed time, never more than burst
+func refill(tokens float64, elapsed time.Duration, limit Limit) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+}
+
+// waitFor returns the time until tokens reaches one
+func waitFor(tokens float64, limit Limit) time.Duration {
+	return time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis"
+	"github.com/stretchr/testify/require"
+)
+
+var ctx = context.Background()
+
+// Help function to run the same checks in memory and in redis, clock is moved by advance
+func testTokenBucket(t *testing.T, limiter Limiter, advance func(time.Duration)) {
+	limit := Limit{Rate: 2, Burst: 3}
+
+	for i := 0; i < 3; i++ {
+		allowed, _, err := limiter.Allow(ctx, "Upvote:user:voter-1", limit)
+		require.Nil(t, err)
+		require.True(t, allowed)
+	}
+
+	allowed, retryAfter, err := limiter.Allow(ctx, "Upvote:user:voter-1", limit)
+	require.Nil(t, err)
+	require.False(t, allowed)
+	require.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// other caller has its own bucket
+	allowed, _, _ = limiter.Allow(ctx, "Upvote:user:voter-2", limit)
+	require.True(t, allowed)
+
+	advance(500 * time.Millisecond)
+	allowed, _, _ = limiter.Allow(ctx, "Upvote:user:voter-1", limit)
+	require.True(t, allowed)
+	allowed, _, _ = limiter.Allow(ctx, "Upvote:user:voter-1", limit)
+	require.False(t, allowed)
+}
+
+// Testing token bucket in memory
+func TestMemory(t *testing.T) {
+	now := time.Now()
+	limiter := NewMemory()
+	limiter.now = func() time.Time { return now }
+
+	testTokenBucket(t, limiter, func(d time.Duration) { now = now.Add(d) })
+}
+
+// Testing token bucket in redis, with the script
+func TestRedis(t *testing.T) {
+	server := miniredis.RunT(t)
+	now := time.Now()
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer rdb.Close()
+	limiter := NewRedis(rdb)
+	limiter.now = func() time.Time { return now }
+
+	testTokenBucket(t, limiter, func(d time.Duration) { now = now.Add(d) })
+	require.True(t, server.Exists("RateLimit-Upvote:user:voter-1"))
+}
+
+// Testing unlimited does not take tokens
+func TestUnlimited(t *testing.T) {
+	limiter := NewMemory()
+
+	for i := 0; i < 100; i++ {
+		allowed, _, err := limiter.Allow(ctx, "ListAllCryptos:ip:127.0.0.1", Limit{})
+		require.Nil(t, err)
+		require.True(t, allowed)
+	}
+	require.Empty(t, limiter.buckets)
+}
+
+// Testing limits of env by method
+func TestLoadConfigWithMethods(t *testing.T) {
+	t.Setenv("RATELIMIT_METHODS", "Upvote=1:2,SearchCryptos=10:20")
+
+	cfg, err := LoadConfig()
+	require.Nil(t, err)
+	require.Equal(t, Limit{Rate: 1, Burst: 2}, cfg.For("/proto.EndPointCryptos/Upvote"))
+	require.Equal(t, Limit{Rate: 10, Burst: 20}, cfg.For("/proto.EndPointCryptos/SearchCryptos"))
+	require.Equal(t, Limit{Rate: 2, Burst: 5}, cfg.For("/proto.EndPointCryptos/CreateCrypto"))
+	require.True(t, cfg.For("/proto.EndPointCryptos/FindCrypto").Unlimited())
+
+	t.Setenv("RATELIMIT_METHODS", "Upvote=fast")
+	_, err = LoadConfig()
+	require.NotNil(t, err)
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// Same bucket of Memory in a hash of redis, the script runs atomic for all replicas.
+// Returns {allowed, retry after in ms}.
+var tokenBucket = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "last")
+local tokens = tonumber(bucket[1])
+local last = tonumber(bucket[2])
+if tokens == nil then
+	tokens = burst
+	last = now
+end
+
+local elapsed = math.max(0, now - last)
+tokens = math.min(burst, tokens + elapsed * rate / 1000)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) * 1000 / rate)
+end
+
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "last", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return {allowed, retry}
+`)
+
+// Redis keeps the buckets in redis, the limits hold across replicas. The pool of connections is of the caller
+type Redis struct {
+	rdb    *redis.Client
+	prefix string
+	now    func() time.Time
+}
+
+var _ Limiter = (*Redis)(nil)
+
+func NewRedis(rdb *redis.Client) *Redis {
+	return &Redis{rdb: rdb, prefix: "RateLimit-", now: time.Now}
+}
+
+func (r *Redis) Allow(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	if limit.Unlimited() {
+		return true, 0, nil
+	}
+
+	now := r.now().UnixNano() / int64(time.Millisecond)
+	result, err := tokenBucket.Run(r.rdb.WithContext(ctx), []string{r.prefix + key},
+		strconv.FormatFloat(limit.Rate, 'f', -1, 64), limit.Burst, now).Result()
+	if err != nil {
+		return false, 0, err
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 2 {
+		return false, 0, errors.New("result of rate limit script is invalid")
+	}
+	allowed, _ := values[0].(int64)
+	retry, _ := values[1].(int64)
+
+	return allowed == 1, time.Duration(math.Max(0, float64(retry))) * time.Millisecond, nil
+}
@@ -0,0 +1,234 @@
+package cache
+
+import (
+	"api-desafio-kvr/helpers"
+	"api-desafio-kvr/metrics"
+	"api-desafio-kvr/models"
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+var logger = &helpers.Log{}
+var YesDeleteAll = true
+var NoDeleteAll = false
+var PrefixDeleteAll = "ListAll"
+var nameLog = "CACHE"
+
+// Cache são criados em todas as operações do controller (exceto exclusao de crypto)
+// Toda vez que é realizada uma operação de criação/edição,
+// o cache de ListAll é apagado, evitando assim um cache desatualizado.
+type Cache interface {
+	Get(ctx context.Context, key string) []byte
+	// Remember returns the cache of key, in a miss the value is loaded and cached
+	Remember(ctx context.Context, key string, load Load) ([]byte, error)
+	Set(ctx context.Context, key string, crypto models.CryptoCurrency, deleteAll bool) error
+	SetByByte(ctx context.Context, key string, value string, deleteAll bool) error
+	Del(ctx context.Context, key string) error
+	// DeleteAll deletes the keys with PrefixDeleteAll
+	DeleteAll(ctx context.Context) error
+	Ping() error
+	Close() error
+}
+
+const (
+	DriverRedis  = "redis"
+	DriverMemory = "memory"
+	DriverNone   = "none"
+)
+
+// Keys expire by ItemTTL (one crypto) or ListTTL (PrefixDeleteAll), with jitter
+// so keys created together do not expire together
+type TTL struct {
+	ItemTTL   helpers.Duration `json:"item_ttl"`
+	ListTTL   helpers.Duration `json:"list_ttl"`
+	TTLJitter float64          `json:"ttl_jitter"` // fraction of ttl added or removed at random, 0.1 = +-10%
+}
+
+type Config struct {
+	Driver     string `json:"driver"`      // redis, memory or none
+	MemorySize int    `json:"memory_size"` // max keys of driver memory
+	TTL
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Driver:     DriverRedis,
+		MemorySize: 1000,
+		TTL: TTL{
+			ItemTTL:   helpers.Duration{Duration: 10 * time.Minute},
+			ListTTL:   helpers.Duration{Duration: time.Minute},
+			TTLJitter: 0.1,
+		},
+	}
+}
+
+// LoadConfig reads section "cache" of CONFIG_FILE and the env vars CACHE_*
+func LoadConfig() (Config, error) {
+	cfg := DefaultConfig()
+
+	err := helpers.LoadConfigFile("cache", &cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	envs := map[string]interface{}{
+		"CACHE_DRIVER":      &cfg.Driver,
+		"CACHE_MEMORY_SIZE": &cfg.MemorySize,
+		"CACHE_ITEM_TTL":    &cfg.ItemTTL,
+		"CACHE_LIST_TTL":    &cfg.ListTTL,
+		"CACHE_TTL_JITTER":  &cfg.TTLJitter,
+	}
+	for key, target := range envs {
+		err = helpers.SetFromEnv(key, target)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	switch cfg.Driver {
+	case DriverRedis, DriverMemory, DriverNone:
+	default:
+		return cfg, errors.New("cache driver is invalid: " + cfg.Driver)
+	}
+
+	if cfg.TTLJitter < 0 || cfg.TTLJitter >= 1 {
+		return cfg, errors.New("cache ttl jitter must be between 0 and 1")
+	}
+
+	return cfg, nil
+}
+
+// For returns ttl of key by its class, 0 is never expire
+func (t TTL) For(key string) time.Duration {
+	ttl := t.ItemTTL.Duration
+	if strings.HasPrefix(key, PrefixDeleteAll) {
+		ttl = t.ListTTL.Duration
+	}
+
+	return Jitter(ttl, t.TTLJitter)
+}
+
+// Jitter returns ttl plus or minus a random part of it
+func Jitter(ttl time.Duration, fraction float64) time.Duration {
+	if ttl <= 0 || fraction <= 0 {
+		return ttl
+	}
+
+	delta := float64(ttl) * fraction * (2*rand.Float64() - 1)
+	return ttl + time.Duration(delta)
+}
+
+// Lookup is the read of one cache, nil value and nil error is a miss
+type Lookup func(ctx context.Context, key string) ([]byte, error)
+
+// Load reads the value of a miss, its ctx does not end with the ctx of the callers waiting it
+type Load func(ctx context.Context) ([]byte, error)
+
+// Store writes the value loaded, without invalidating the other loads
+type Store func(ctx context.Context, key string, value []byte) error
+
+// Max time of one load, it is shared by all callers of the key
+const LoadTimeout = 10 * time.Second
+
+// Loads shares the load of a key by the concurrent misses. Every write of cache invalidates
+// the loads in progress: they return the value read but do not cache it, it may be older than the write.
+type Loads struct {
+	group      singleflight.Group
+	mu         sync.RWMutex
+	generation uint64
+}
+
+// Invalidate must be called by each write of cache before it writes
+func (l *Loads) Invalidate() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.generation++
+}
+
+func (l *Loads) current() uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.generation
+}
+
+// Writes the value only if no write was made after generation, the write waits it
+func (l *Loads) store(ctx context.Context, key string, value []byte, generation uint64, set Store) (bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.generation != generation {
+		return false, nil
+	}
+	return true, set(ctx, key, value)
+}
+
+// detached keeps the values of ctx (trace, logger of request) without its cancel and deadline
+type detached struct{ context.Context }
+
+func (detached) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detached) Done() <-chan struct{}       { return nil }
+func (detached) Err() error                  { return nil }
+
+// RememberWith implements Remember to the caches, concurrent misses of same key wait for one load.
+// A caller canceled stops waiting, the load goes on to the others. The value is not cached when
+// the cache was written during the load.
+// Hits, misses and errors of lookup are counted by path: list (PrefixDeleteAll) or find.
+func RememberWith(ctx context.Context, loads *Loads, key string, lookup Lookup, set Store, load Load) ([]byte, error) {
+	path := "find"
+	if strings.HasPrefix(key, PrefixDeleteAll) {
+		path = "list"
+	}
+
+	cache, err := lookup(ctx, key)
+	switch {
+	case err != nil:
+		logger.Error(key, "Error to get cache: "+err.Error())
+		metrics.ObserveCache(path, metrics.CacheError)
+	case cache != nil:
+		metrics.ObserveCache(path, metrics.CacheHit)
+		return cache, nil
+	default:
+		metrics.ObserveCache(path, metrics.CacheMiss)
+	}
+
+	results := loads.group.DoChan(key, func() (interface{}, error) {
+		loadCtx, cancel := context.WithTimeout(detached{ctx}, LoadTimeout)
+		defer cancel()
+
+		generation := loads.current()
+		value, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		stored, err := loads.store(loadCtx, key, value, generation, set)
+		if err != nil {
+			logger.Error(key, "Error to set cache: "+err.Error())
+		}
+		if !stored {
+			logger.Debug(nameLog, "Cache written during load, not set key: "+key)
+		}
+		return value, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		if result.Shared {
+			logger.Debug(nameLog, "Load shared for key: "+key)
+		}
+		return result.Val.([]byte), nil
+	}
+}
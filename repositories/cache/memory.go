@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"api-desafio-kvr/models"
+	"container/list"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Memory is a LRU cache in process, when it is full the key used less recently is removed
+type Memory struct {
+	mu      sync.Mutex
+	size    int
+	ttl     TTL
+	order   *list.List // front is the most recently used
+	entries map[string]*list.Element
+	loads   Loads
+}
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // zero is never expire
+}
+
+func NewMemory(size int, ttl TTL) *Memory {
+	logger.Info(nameLog, "Starting cache in memory with "+strconv.Itoa(size)+" keys")
+	if size <= 0 {
+		size = DefaultConfig().MemorySize
+	}
+
+	return &Memory{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+func (m *Memory) Get(ctx context.Context, key string) []byte {
+	logger.Debug(nameLog, "Getting cache for key: "+key)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	element, ok := m.entries[key]
+	if !ok {
+		return nil
+	}
+
+	entry := element.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		m.remove(element)
+		return nil
+	}
+
+	m.order.MoveToFront(element)
+	return entry.value
+}
+
+func (m *Memory) Remember(ctx context.Context, key string, load Load) ([]byte, error) {
+	return RememberWith(ctx, &m.loads, key, m.lookup, m.set, load)
+}
+
+func (m *Memory) Set(ctx context.Context, key string, crypto models.CryptoCurrency, deleteAll bool) error {
+	byteValue, err := json.Marshal(crypto)
+	if err != nil {
+		logger.Error(crypto.Id.Hex(), "Error in response: "+err.Error())
+		return err
+	}
+
+	return m.SetByByte(ctx, key, string(byteValue), deleteAll)
+}
+
+func (m *Memory) lookup(ctx context.Context, key string) ([]byte, error) {
+	return m.Get(ctx, key), nil
+}
+
+func (m *Memory) SetByByte(ctx context.Context, key string, value string, deleteAll bool) error {
+	m.loads.Invalidate()
+	m.set(ctx, key, []byte(value))
+
+	if deleteAll {
+		return m.DeleteAll(ctx)
+	}
+	return nil
+}
+
+func (m *Memory) set(ctx context.Context, key string, value []byte) error {
+	logger.Debug(nameLog, "Setting cache for key: "+key)
+
+	entry := &memoryEntry{key: key, value: value}
+	ttl := m.ttl.For(key)
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	m.mu.Lock()
+	if element, ok := m.entries[key]; ok {
+		element.Value = entry
+		m.order.MoveToFront(element)
+	} else {
+		m.entries[key] = m.order.PushFront(entry)
+	}
+
+	for m.order.Len() > m.size {
+		m.remove(m.order.Back())
+	}
+	m.mu.Unlock()
+
+	return nil
+}
+
+func (m *Memory) Del(ctx context.Context, key string) error {
+	logger.Debug(nameLog, "Deleting cache for key: "+key)
+	m.loads.Invalidate()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if element, ok := m.entries[key]; ok {
+		m.remove(element)
+	}
+	return nil
+}
+
+func (m *Memory) DeleteAll(ctx context.Context) error {
+	logger.Debug(nameLog, "Deleting cache for "+PrefixDeleteAll)
+	m.loads.Invalidate()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, element := range m.entries {
+		if strings.HasPrefix(key, PrefixDeleteAll) {
+			m.remove(element)
+		}
+	}
+	return nil
+}
+
+func (m *Memory) Ping() error {
+	return nil
+}
+
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.order.Init()
+	m.entries = map[string]*list.Element{}
+	return nil
+}
+
+// must be called with m.mu locked
+func (m *Memory) remove(element *list.Element) {
+	m.order.Remove(element)
+	delete(m.entries, element.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"api-desafio-kvr/helpers"
+	"api-desafio-kvr/metrics"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// Testing the key used less recently is removed when memory is full
+func TestMemoryEvictsLeastRecentlyUsed(t *testing.T) {
+	memory := NewMemory(2, TTL{})
+	require.Nil(t, memory.SetByByte(ctx, "first", "1", NoDeleteAll))
+	require.Nil(t, memory.SetByByte(ctx, "second", "2", NoDeleteAll))
+
+	require.Equal(t, []byte("1"), memory.Get(ctx, "first"))
+	require.Nil(t, memory.SetByByte(ctx, "third", "3", NoDeleteAll))
+
+	require.Nil(t, memory.Get(ctx, "second"))
+	require.Equal(t, []byte("1"), memory.Get(ctx, "first"))
+	require.Equal(t, []byte("3"), memory.Get(ctx, "third"))
+}
+
+// Testing expired key is not returned
+func TestMemoryWithExpiredKey(t *testing.T) {
+	memory := NewMemory(10, TTL{ItemTTL: durationOf(time.Millisecond)})
+	require.Nil(t, memory.SetByByte(ctx, "crypto-1", "1", NoDeleteAll))
+
+	time.Sleep(5 * time.Millisecond)
+
+	require.Nil(t, memory.Get(ctx, "crypto-1"))
+}
+
+// Testing set with delete all removes only the keys of lists
+func TestMemorySetWithDeleteAll(t *testing.T) {
+	memory := NewMemory(10, TTL{})
+	require.Nil(t, memory.SetByByte(ctx, PrefixDeleteAll+"-name-true", "[]", NoDeleteAll))
+	require.Nil(t, memory.SetByByte(ctx, "crypto-1", "1", YesDeleteAll))
+
+	require.Nil(t, memory.Get(ctx, PrefixDeleteAll+"-name-true"))
+	require.Equal(t, []byte("1"), memory.Get(ctx, "crypto-1"))
+}
+
+// Testing remember loads in a miss and uses the cache after
+func TestMemoryRemember(t *testing.T) {
+	memory := NewMemory(10, TTL{})
+	loads := 0
+	load := func(ctx context.Context) ([]byte, error) {
+		loads++
+		return []byte("1"), nil
+	}
+
+	for i := 0; i < 3; i++ {
+		value, err := memory.Remember(ctx, "crypto-1", load)
+		require.Nil(t, err)
+		require.Equal(t, []byte("1"), value)
+	}
+	require.Equal(t, 1, loads)
+}
+
+// Testing the caller canceled stops waiting and the shared load goes on to the others
+func TestMemoryRememberWithCallerCanceled(t *testing.T) {
+	memory := NewMemory(10, TTL{})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	load := func(ctx context.Context) ([]byte, error) {
+		close(started)
+		<-release
+		return []byte("1"), ctx.Err()
+	}
+
+	first, cancel := context.WithCancel(ctx)
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := memory.Remember(first, "crypto-1", load)
+		firstErr <- err
+	}()
+	<-started
+
+	second := make(chan []byte, 1)
+	go func() {
+		value, _ := memory.Remember(ctx, "crypto-1", load)
+		second <- value
+	}()
+
+	cancel()
+	require.Equal(t, context.Canceled, <-firstErr)
+
+	close(release)
+	require.Equal(t, []byte("1"), <-second)
+	require.Equal(t, []byte("1"), memory.Get(ctx, "crypto-1"))
+}
+
+// Testing the value loaded is not cached when the key was written during the load
+func TestMemoryRememberWithWriteDuringLoad(t *testing.T) {
+	memory := NewMemory(10, TTL{})
+
+	value, err := memory.Remember(ctx, "crypto-1", func(ctx context.Context) ([]byte, error) {
+		require.Nil(t, memory.Del(ctx, "crypto-1"))
+		return []byte("old"), nil
+	})
+
+	require.Nil(t, err)
+	require.Equal(t, []byte("old"), value)
+	require.Nil(t, memory.Get(ctx, "crypto-1"))
+}
+
+// Testing remember counts miss and hit by path of key
+func TestRememberMetrics(t *testing.T) {
+	memory := NewMemory(10, TTL{})
+	key := PrefixDeleteAll + "-name-true-50-"
+	hits := testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("list", metrics.CacheHit))
+	misses := testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("list", metrics.CacheMiss))
+
+	for i := 0; i < 2; i++ {
+		_, err := memory.Remember(ctx, key, func(ctx context.Context) ([]byte, error) { return []byte("[]"), nil })
+		require.Nil(t, err)
+	}
+
+	require.Equal(t, hits+1, testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("list", metrics.CacheHit)))
+	require.Equal(t, misses+1, testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("list", metrics.CacheMiss)))
+}
+
+// Testing error of lookup is counted and the value is loaded
+func TestRememberWithLookupError(t *testing.T) {
+	noop := NewNoop()
+	failures := testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("find", metrics.CacheError))
+	lookup := func(ctx context.Context, key string) ([]byte, error) {
+		return nil, fmt.Errorf("connection refused")
+	}
+
+	value, err := RememberWith(ctx, &noop.loads, "crypto-1", lookup, noop.set, func(ctx context.Context) ([]byte, error) { return []byte("1"), nil })
+
+	require.Nil(t, err)
+	require.Equal(t, []byte("1"), value)
+	require.Equal(t, failures+1, testutil.ToFloat64(metrics.CacheRequests.WithLabelValues("find", metrics.CacheError)))
+}
+
+// Testing error of load is returned and not cached
+func TestNoopRememberWithError(t *testing.T) {
+	noop := NewNoop()
+
+	_, err := noop.Remember(ctx, "crypto-1", func(ctx context.Context) ([]byte, error) {
+		return nil, errors.New("some error")
+	})
+
+	require.NotNil(t, err)
+	require.Nil(t, noop.Get(ctx, "crypto-1"))
+}
+
+// Testing jitter keeps ttl inside the fraction
+func TestJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		ttl := Jitter(time.Minute, 0.1)
+		require.GreaterOrEqual(t, ttl, 54*time.Second)
+		require.LessOrEqual(t, ttl, 66*time.Second)
+	}
+	require.Equal(t, time.Duration(0), Jitter(0, 0.1))
+}
+
+var ctx = context.Background()
+
+func durationOf(d time.Duration) helpers.Duration {
+	return helpers.Duration{Duration: d}
+}
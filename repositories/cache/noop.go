@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"api-desafio-kvr/models"
+	"context"
+)
+
+// Noop never keeps a value, all reads go to database.
+// Concurrent loads of same key are still shared.
+type Noop struct {
+	loads Loads
+}
+
+func NewNoop() *Noop {
+	logger.Info(nameLog, "Cache is disabled")
+	return &Noop{}
+}
+
+func (n *Noop) Get(ctx context.Context, key string) []byte {
+	return nil
+}
+
+func (n *Noop) Remember(ctx context.Context, key string, load Load) ([]byte, error) {
+	return RememberWith(ctx, &n.loads, key, n.lookup, n.set, load)
+}
+
+func (n *Noop) Set(ctx context.Context, key string, crypto models.CryptoCurrency, deleteAll bool) error {
+	return nil
+}
+
+func (n *Noop) lookup(ctx context.Context, key string) ([]byte, error) {
+	return n.Get(ctx, key), nil
+}
+
+func (n *Noop) set(ctx context.Context, key string, value []byte) error {
+	return nil
+}
+
+func (n *Noop) SetByByte(ctx context.Context, key string, value string, deleteAll bool) error {
+	return nil
+}
+
+func (n *Noop) Del(ctx context.Context, key string) error {
+	return nil
+}
+
+func (n *Noop) DeleteAll(ctx context.Context) error {
+	return nil
+}
+
+func (n *Noop) Ping() error {
+	return nil
+}
+
+func (n *Noop) Close() error {
+	return nil
+}
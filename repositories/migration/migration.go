@@ -4,6 +4,7 @@ import (
 	"api-desafio-kvr/helpers"
 	"api-desafio-kvr/models"
 	"api-desafio-kvr/repositories/mongodb"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"os"
@@ -16,8 +17,10 @@ import (
 var logger = &helpers.Log{}
 var nameLog = "MIGRATION"
 
-func CreateInitialCryptosBulk(collection mongodb.IMCollection) {
-	countDoc, err := mongodb.CountDocuments(collection)
+// Imports the cryptos of file in an empty collection, each one with its first price in history as CreateCrypto
+func CreateInitialCryptosBulk(ctx context.Context, collection mongodb.IMCollection, prices mongodb.IMCollection) {
+	// deleted cryptos count, so the file is not imported again over them
+	countDoc, err := mongodb.CountAllDocuments(ctx, collection)
 	if err != nil {
 		logger.Error(nameLog, "Error in migration CountDocuments: "+err.Error())
 		return
@@ -40,9 +43,15 @@ func CreateInitialCryptosBulk(collection mongodb.IMCollection) {
 		cryptos[i].CreatedAt = time.Now()
 		cryptos[i].UpdatedAt = time.Now()
 
-		_, err := mongodb.InsertCryptos(collection, cryptos[i])
+		inserted, err := mongodb.InsertCryptos(ctx, collection, cryptos[i])
 		if err != nil {
 			logger.Error("", "Error in import "+err.Error())
+			continue
+		}
+
+		_, err = mongodb.RecordPrice(ctx, prices, inserted.Id, inserted.PriceUsd, inserted.UpdatedAt)
+		if err != nil {
+			logger.Error(inserted.Id.Hex(), "Error to record price in import "+err.Error())
 		}
 		logger.Debug("", "Crypto "+cryptos[i].Name+" imported")
 	}
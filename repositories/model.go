@@ -1,5 +1,11 @@
 package repositories
 
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
 // Params to sort query
 type SortParams struct {
 	Field string
@@ -9,3 +15,28 @@ type SortParams struct {
 func SortDefault() SortParams {
 	return SortParams{"name", true}
 }
+
+// Params to paginate query, Token is empty to first page and Size 0 has no limit
+type PageParams struct {
+	Size  int64
+	Token string
+}
+
+// Params to filter search query, nil or empty values are not filtered
+type SearchParams struct {
+	Name       string
+	NamePrefix bool
+	AssetId    string
+	MinPrice   *float64
+	MaxPrice   *float64
+	MinVotes   *int32
+	MaxVotes   *int32
+}
+
+// Params to filter audit events, zero values are not filtered
+type AuditParams struct {
+	CryptoId primitive.ObjectID
+	Actor    string
+	From     time.Time
+	To       time.Time
+}
@@ -0,0 +1,122 @@
+package mongodb
+
+import (
+	"api-desafio-kvr/models"
+	"api-desafio-kvr/repositories"
+	"context"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const AUDIT_COLLECTION = "audit_events"
+
+func GetAuditCollection(client *mongo.Client) *mongo.Collection {
+	return client.Database(current.Database).Collection(current.AuditCollection)
+}
+
+// Events are listed by crypto or by actor, the newest first
+func CreateAuditIndexes(coll *mongo.Collection) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    primitive.D{{Key: "crypto_id", Value: 1}, {Key: "time", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("crypto_time"),
+		},
+		{
+			Keys:    primitive.D{{Key: "actor", Value: 1}, {Key: "time", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("actor_time"),
+		},
+		{
+			Keys:    primitive.D{{Key: "time", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("time"),
+		},
+	}
+
+	_, err := coll.Indexes().CreateMany(context.Background(), indexes)
+
+	logger.Debug("", "Indexes of "+current.AuditCollection+" created...")
+	return err
+}
+
+var InsertAuditEvent = func(ctx context.Context, coll IMCollection, event models.AuditEvent) (err error) {
+	ctx, end := startOperation(ctx, "insert_audit_event", current.AuditCollection)
+	defer end(&err)
+
+	if event.Id.IsZero() {
+		event.Id = primitive.NewObjectID()
+	}
+
+	_, err = coll.InsertOne(ctx, event)
+
+	logger.DebugContext(ctx, event.CryptoId.Hex(), "Audit event of "+event.RpcMethod+" by "+event.Actor+" inserted...")
+	return err
+}
+
+// Returns one page of events, the newest first, and the token to next page, token is empty in last page
+var ListAuditEvents = func(ctx context.Context, coll IMCollection, filter repositories.AuditParams, page repositories.PageParams) (result []models.AuditEvent, nextToken string, err error) {
+	ctx, end := startOperation(ctx, "list_audit_events", current.AuditCollection)
+	defer end(&err)
+	where := QueryToAudit(filter)
+
+	if page.Token != "" {
+		var token pageToken
+		token, err = decodePageToken(page.Token, "time", false)
+		if err != nil {
+			return result, nextToken, err
+		}
+		where = bson.M{"$and": []interface{}{where, afterPageToken(token)}}
+	}
+
+	// Find one more to know if exists next page
+	sort := primitive.D{{Key: "time", Value: -1}, {Key: "_id", Value: -1}}
+	opts := options.Find().SetSort(sort).SetLimit(page.Size + 1)
+	cursor, err := coll.Find(ctx, where, opts)
+	if err != nil {
+		logger.ErrorContext(ctx, "", "Error in find ListAuditEvents: "+err.Error())
+		return result, nextToken, err
+	}
+
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &result)
+	if err != nil {
+		return result, nextToken, err
+	}
+
+	if int64(len(result)) > page.Size {
+		result = result[:page.Size]
+		last := result[len(result)-1]
+		nextToken, err = encodeToken(pageToken{Field: "time", Asc: false, Value: last.Time, Id: last.Id})
+	}
+
+	logger.DebugContext(ctx, "", "Returning "+strconv.Itoa(len(result))+" audit events...")
+	return result, nextToken, err
+}
+
+var QueryToAudit = func(filter repositories.AuditParams) bson.M {
+	where := bson.M{}
+
+	if !filter.CryptoId.IsZero() {
+		where["crypto_id"] = filter.CryptoId
+	}
+
+	if filter.Actor != "" {
+		where["actor"] = filter.Actor
+	}
+
+	period := bson.M{}
+	if !filter.From.IsZero() {
+		period["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		period["$lt"] = filter.To
+	}
+	if len(period) > 0 {
+		where["time"] = period
+	}
+
+	return where
+}
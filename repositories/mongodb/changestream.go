@@ -0,0 +1,121 @@
+package mongodb
+
+import (
+	"api-desafio-kvr/models"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const RESUME_TOKENS_COLLECTION = "resume_tokens"
+
+// Tokens not saved in this time are removed, the oplog does not keep changes so old
+const ResumeTokenTTL = 7 * 24 * time.Hour
+
+// Codes of server when the resume token is not in the oplog anymore
+const (
+	codeChangeStreamFatal       = 280
+	codeChangeStreamHistoryLost = 286
+)
+
+// Implemented by *mongo.ChangeStream
+type ChangeStream interface {
+	Next(ctx context.Context) bool
+	Decode(val interface{}) error
+	ResumeToken() bson.Raw
+	Err() error
+	Close(ctx context.Context) error
+}
+
+// Change of one crypto read from the change stream
+type CryptoChange struct {
+	Token struct {
+		Data string `bson:"_data"`
+	} `bson:"_id"` // resume token, the same change has the same token in all watchers
+	OperationType string `bson:"operationType"` // insert, update, replace, delete or invalidate
+	DocumentKey   struct {
+		Id primitive.ObjectID `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument      *models.CryptoCurrency `bson:"fullDocument"` // crypto when the change is read, nil after delete
+	UpdateDescription struct {
+		UpdatedFields bson.M   `bson:"updatedFields"`
+		RemovedFields []string `bson:"removedFields"`
+	} `bson:"updateDescription"`
+}
+
+type resumeToken struct {
+	Id        string    `bson:"_id"` // id of watcher
+	Token     bson.Raw  `bson:"token"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+func GetResumeTokensCollection(client *mongo.Client) *mongo.Collection {
+	return client.Database(current.Database).Collection(current.ResumeTokensCollection)
+}
+
+// Tokens of watchers that stopped (replicas removed) expire
+func CreateResumeTokensIndexes(coll *mongo.Collection) error {
+	index := mongo.IndexModel{
+		Keys:    primitive.D{{Key: "updated_at", Value: 1}},
+		Options: options.Index().SetName("updated_at_ttl").SetExpireAfterSeconds(int32(ResumeTokenTTL.Seconds())),
+	}
+
+	_, err := coll.Indexes().CreateOne(context.Background(), index)
+
+	logger.Debug("", "Indexes of "+current.ResumeTokensCollection+" created...")
+	return err
+}
+
+// Opens the change stream of cryptos after the token, nil token starts from now
+var WatchCryptos = func(ctx context.Context, coll *mongo.Collection, token bson.Raw) (ChangeStream, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"operationType": bson.M{"$in": []string{"insert", "update", "replace", "delete", "invalidate"}}}}},
+	}
+
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if token != nil {
+		opts.SetResumeAfter(token)
+	}
+
+	return coll.Watch(ctx, pipeline, opts)
+}
+
+// Says if the stream can not resume from the token and must start again from now
+func IsResumeTokenLost(err error) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+	return serverErr.HasErrorCode(codeChangeStreamHistoryLost) || serverErr.HasErrorCode(codeChangeStreamFatal)
+}
+
+// Returns the last token saved by the watcher, nil when it never saved one
+var GetResumeToken = func(ctx context.Context, coll IMCollection, watcherId string) (bson.Raw, error) {
+	var saved resumeToken
+	err := coll.FindOne(ctx, bson.M{"_id": watcherId}).Decode(&saved)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return saved.Token, nil
+}
+
+// Nil token removes the saved one, the watcher starts from now in next time
+var SaveResumeToken = func(ctx context.Context, coll IMCollection, watcherId string, token bson.Raw) error {
+	if token == nil {
+		_, err := coll.DeleteOne(ctx, bson.M{"_id": watcherId})
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{"token": token, "updated_at": time.Now()}}
+	_, err := coll.UpdateOne(ctx, bson.M{"_id": watcherId}, update, options.Update().SetUpsert(true))
+	return err
+}
@@ -7,7 +7,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -16,110 +18,351 @@ import (
 	"gopkg.in/mgo.v2/bson"
 )
 
-const (
-	DATABASE   = "kvrDb"
-	COLLECTION = "cryptos"
-)
-
 func GetDataBase(client *mongo.Client) *mongo.Collection {
-	return client.Database(DATABASE).Collection(COLLECTION)
+	return client.Database(current.Database).Collection(current.Collection)
 }
 
 func NameCollection() string {
-	return COLLECTION
+	return current.Collection
+}
+
+// Deleted cryptos keep deleted_at until restored or purged, the rpcs do not see them.
+// null also matches the documents without the field
+func notDeleted() bson.M {
+	return bson.M{"deleted_at": nil}
+}
+
+// Index of old versions, unique also to the deleted cryptos
+const oldAssetIndex = "asset_id_unique"
+
+// Code of server when the index to drop does not exist
+const codeIndexNotFound = 27
+
+// asset_id is unique between the cryptos not deleted, two of them can not have the same ticker.
+// The asset_id of a deleted crypto can be used by a new one, then the deleted is not restored
+func CreateCryptosIndexes(coll *mongo.Collection) error {
+	ctx := context.Background()
+
+	// the documents of old versions have not the field deleted, out of the partial index
+	_, err := coll.UpdateMany(ctx, bson.M{"deleted": bson.M{"$exists": false}, "deleted_at": nil}, bson.M{"$set": bson.M{"deleted": false}})
+	if err != nil {
+		return err
+	}
+	_, err = coll.UpdateMany(ctx, bson.M{"deleted": bson.M{"$exists": false}, "deleted_at": bson.M{"$ne": nil}}, bson.M{"$set": bson.M{"deleted": true}})
+	if err != nil {
+		return err
+	}
+
+	// same keys of the new index, it is removed before
+	_, err = coll.Indexes().DropOne(ctx, oldAssetIndex)
+	if err != nil && !isIndexNotFound(err) {
+		return err
+	}
+
+	index := mongo.IndexModel{
+		Keys: primitive.D{{Key: "asset_id", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("asset_id_unique_not_deleted").
+			SetPartialFilterExpression(bson.M{"deleted": false}),
+	}
+
+	_, err = coll.Indexes().CreateOne(ctx, index)
+
+	logger.Debug("", "Indexes of "+current.Collection+" created...")
+	return err
+}
+
+func isIndexNotFound(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorCode(codeIndexNotFound)
 }
 
-var InsertCryptos = func(coll IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, error) {
+var InsertCryptos = func(ctx context.Context, coll IMCollection, crypto models.CryptoCurrency) (_ models.CryptoCurrency, err error) {
+	ctx, end := startOperation(ctx, "insert_crypto", current.Collection)
+	defer end(&err)
 	crypto.PrepateToInsert()
 
-	result, err := coll.InsertOne(context.Background(), crypto)
+	result, err := coll.InsertOne(ctx, crypto)
+	if err != nil {
+		crypto.RevertPrepateToInsert()
+		return crypto, err
+	}
 
 	if result.InsertedID == nil {
 		crypto.RevertPrepateToInsert()
 		return crypto, errors.New("some error to insert")
 	}
 
-	logger.Debug(crypto.Id.Hex(), "Crypto inserted...")
+	logger.DebugContext(ctx, crypto.Id.Hex(), "Crypto inserted...")
 	return crypto, err
 }
 
-var GetById = func(coll IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
-	err = coll.FindOne(context.Background(), bson.M{"_id": id}).Decode(&crypto)
-	logger.Debug(id.Hex(), "Crypto found...")
+var GetById = func(ctx context.Context, coll IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
+	ctx, end := startOperation(ctx, "get_by_id", current.Collection)
+	defer end(&err)
+	err = coll.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&crypto)
+	logger.DebugContext(ctx, id.Hex(), "Crypto found...")
 	return crypto, err
 }
 
-var ListAll = func(coll IMCollection, sort repositories.SortParams) (result []models.CryptoCurrency, err error) {
+var GetByAsset = func(ctx context.Context, coll IMCollection, assetId string) (crypto models.CryptoCurrency, err error) {
+	ctx, end := startOperation(ctx, "get_by_asset", current.Collection)
+	defer end(&err)
+	err = coll.FindOne(ctx, bson.M{"asset_id": strings.ToUpper(assetId), "deleted_at": nil}).Decode(&crypto)
+	logger.DebugContext(ctx, assetId, "Crypto found by asset...")
+	return crypto, err
+}
+
+var ListAll = func(ctx context.Context, coll IMCollection, sort repositories.SortParams) (result []models.CryptoCurrency, err error) {
+	ctx, end := startOperation(ctx, "list_all", current.Collection)
+	defer end(&err)
 	field, order := OrderBy(sort)
-	cursor, err := coll.Find(context.Background(), bson.M{}, options.Find().SetSort(bson.M{field: order}))
+	cursor, err := coll.Find(ctx, notDeleted(), options.Find().SetSort(bson.M{field: order}))
 	if err != nil {
-		logger.Error("", "Error in find ListAll: "+err.Error())
+		logger.ErrorContext(ctx, "", "Error in find ListAll: "+err.Error())
 		return result, err
 	}
 
-	defer cursor.Close(context.Background())
+	defer cursor.Close(ctx)
 
-	err = cursor.All(context.Background(), &result)
+	err = cursor.All(ctx, &result)
 
-	logger.Debug("", "Returning cryptos...")
+	logger.DebugContext(ctx, "", "Returning cryptos...")
 	return result, err
 }
 
-var UpdateCrypto = func(coll IMCollection, crypto models.CryptoCurrency) (models.CryptoCurrency, int64, error) {
-	var matchedCount int64
+// Returns one page of cryptos and the token to next page, token is empty in last page
+var ListPage = func(ctx context.Context, coll IMCollection, sort repositories.SortParams, page repositories.PageParams) (result []models.CryptoCurrency, nextToken string, err error) {
+	ctx, end := startOperation(ctx, "list_page", current.Collection)
+	defer end(&err)
+	filter, err := pageFilter(sort, page)
+	if err != nil {
+		logger.ErrorContext(ctx, "", "Error in token of ListPage: "+err.Error())
+		return result, nextToken, err
+	}
+	filter["deleted_at"] = nil
+
+	result, nextToken, err = findPage(ctx, coll, filter, sort, page)
+	if err != nil {
+		logger.ErrorContext(ctx, "", "Error in find ListPage: "+err.Error())
+		return result, nextToken, err
+	}
+
+	logger.DebugContext(ctx, "", "Returning page of cryptos...")
+	return result, nextToken, err
+}
+
+// Search is paginated as ListPage, the filter of token is added to the filter of search
+var Search = func(ctx context.Context, coll IMCollection, search repositories.SearchParams, sort repositories.SortParams, page repositories.PageParams) (result []models.CryptoCurrency, nextToken string, err error) {
+	ctx, end := startOperation(ctx, "search", current.Collection)
+	defer end(&err)
+	after, err := pageFilter(sort, page)
+	if err != nil {
+		logger.ErrorContext(ctx, "", "Error in token of Search: "+err.Error())
+		return result, nextToken, err
+	}
+
+	// QueryToSearch has not $or, so the keys of token not replace the search
+	filter := QueryToSearch(search)
+	for key, value := range after {
+		filter[key] = value
+	}
+
+	result, nextToken, err = findPage(ctx, coll, filter, sort, page)
+	if err != nil {
+		logger.ErrorContext(ctx, "", "Error in find Search: "+err.Error())
+		return result, nextToken, err
+	}
+
+	logger.DebugContext(ctx, "", "Returning "+strconv.Itoa(len(result))+" cryptos searched...")
+	return result, nextToken, err
+}
+
+// Counts the cryptos of search in all pages
+var CountSearch = func(ctx context.Context, coll IMCollection, search repositories.SearchParams) (count int64, err error) {
+	ctx, end := startOperation(ctx, "count_search", current.Collection)
+	defer end(&err)
+	count, err = coll.CountDocuments(ctx, QueryToSearch(search))
+
+	logger.DebugContext(ctx, "", "Count cryptos searched "+strconv.FormatInt(count, 10)+" ...")
+	return count, err
+}
+
+// Finds one more than page size to know if exists next page, size 0 finds all
+func findPage(ctx context.Context, coll IMCollection, filter interface{}, sort repositories.SortParams, page repositories.PageParams) (result []models.CryptoCurrency, nextToken string, err error) {
+	field, order := OrderBy(sort)
+	opts := options.Find().SetSort(pageSort(field, order))
+	if page.Size > 0 {
+		opts.SetLimit(page.Size + 1)
+	}
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return result, nextToken, err
+	}
+
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &result)
+	if err != nil {
+		return result, nextToken, err
+	}
+
+	if page.Size > 0 && int64(len(result)) > page.Size {
+		result = result[:page.Size]
+		nextToken, err = encodePageToken(field, sort.Asc, result[len(result)-1])
+	}
+	return result, nextToken, err
+}
+
+// Returns the crypto as it is after the update, so the caller does not read a later write,
+// matchedCount is 0 when no crypto matches (not exists, deleted or other version)
+var UpdateCrypto = func(ctx context.Context, coll IMCollection, crypto models.CryptoCurrency) (_ models.CryptoCurrency, matchedCount int64, err error) {
+	ctx, end := startOperation(ctx, "update_crypto", current.Collection)
+	defer end(&err)
 	// SetUpsert(false) = if not exists then not insert
-	opts := options.Update().SetUpsert(false)
+	opts := options.FindOneAndUpdate().SetUpsert(false).SetReturnDocument(options.After)
 	filter, update, err := QueryToUpdate(crypto)
 	if err != nil {
 		return crypto, matchedCount, err
 	}
 
-	result, err := coll.UpdateOne(context.TODO(), filter, update, opts)
+	var updated models.CryptoCurrency
+	err = coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		return crypto, 0, nil
+	}
+	if err != nil {
+		return crypto, matchedCount, err
+	}
+
+	logger.DebugContext(ctx, crypto.Id.Hex(), "Updated crypto...")
+	return updated, 1, nil
+}
+
+var CountDocuments = func(ctx context.Context, coll IMCollection) (count int64, err error) {
+	ctx, end := startOperation(ctx, "count_documents", current.Collection)
+	defer end(&err)
+	count, err = coll.CountDocuments(ctx, notDeleted())
 
-	logger.Debug(crypto.Id.Hex(), "Updated crypto...")
-	return crypto, result.MatchedCount, err
+	logger.DebugContext(ctx, "", "Count cryptos "+strconv.FormatInt(count, 10)+" ...")
+	return count, err
 }
 
-var CountDocuments = func(coll IMCollection) (count int64, err error) {
-	count, err = coll.CountDocuments(context.Background(), bson.M{})
+// Counts the deleted cryptos too, the migration imports only in an empty collection
+var CountAllDocuments = func(ctx context.Context, coll IMCollection) (count int64, err error) {
+	ctx, end := startOperation(ctx, "count_all_documents", current.Collection)
+	defer end(&err)
+	count, err = coll.CountDocuments(ctx, bson.M{})
 
-	logger.Debug("", "Count cryptos "+strconv.FormatInt(count, 10)+" ...")
+	logger.DebugContext(ctx, "", "Count all cryptos "+strconv.FormatInt(count, 10)+" ...")
 	return count, err
 }
 
-var DeleteAll = func(coll IMCollection) {
-	cryptos, err := ListAll(coll, repositories.SortDefault())
+// Soft deletes all cryptos not deleted yet, as DeleteCrypo does one by one, PurgeByIds removes them for good
+var DeleteAll = func(ctx context.Context, coll IMCollection) {
+	cryptos, err := ListAll(ctx, coll, repositories.SortDefault())
 	if err != nil {
-		logger.Error("", "Error in DeleteAll "+err.Error())
+		logger.ErrorContext(ctx, "", "Error in DeleteAll "+err.Error())
 	}
 
 	deleted := []string{}
 
 	for i := 0; i < len(cryptos); i++ {
-		logger.Debug(cryptos[i].Id.Hex(), "Deleting crypto in delete all")
+		logger.DebugContext(ctx, cryptos[i].Id.Hex(), "Deleting crypto in delete all")
 		deleted = append(deleted, cryptos[i].Id.Hex())
 
-		_, err := DeleteById(coll, cryptos[i].Id)
+		_, err := DeleteById(ctx, coll, cryptos[i].Id)
 		if err != nil {
-			logger.Error(cryptos[i].Id.Hex(), "Error in delete all")
+			logger.ErrorContext(ctx, cryptos[i].Id.Hex(), "Error in delete all")
 		}
 	}
 
 	if len(deleted) < 1 {
-		logger.Debug("", "Documents not deleted "+fmt.Sprint(deleted))
+		logger.DebugContext(ctx, "", "Documents not deleted "+fmt.Sprint(deleted))
 		return
 	}
 
-	logger.Debug("", "Deleted all documents with id "+fmt.Sprint(deleted))
+	logger.DebugContext(ctx, "", "Deleted all documents with id "+fmt.Sprint(deleted))
+}
+
+// Soft delete, sets deleted_at and keeps the document and its votes to RestoreById, returns the crypto deleted
+var DeleteById = func(ctx context.Context, coll IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
+	ctx, end := startOperation(ctx, "delete_by_id", current.Collection)
+	defer end(&err)
+
+	now := time.Now()
+	update := bson.M{"$set": bson.M{"deleted_at": now, "deleted": true, "updated_at": now}, "$inc": bson.M{"version": 1}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = coll.FindOneAndUpdate(ctx, bson.M{"_id": id, "deleted_at": nil}, update, opts).Decode(&crypto)
+
+	logger.DebugContext(ctx, id.Hex(), "Document deleted...")
+	return crypto, err
+}
+
+// Returns the crypto only if it is deleted, ErrNoDocuments if not exists or it is not deleted
+var GetDeletedById = func(ctx context.Context, coll IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
+	ctx, end := startOperation(ctx, "get_deleted_by_id", current.Collection)
+	defer end(&err)
+	err = coll.FindOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}).Decode(&crypto)
+	logger.DebugContext(ctx, id.Hex(), "Crypto deleted found...")
+	return crypto, err
+}
+
+// Undo the soft delete, returns the crypto restored or ErrNoDocuments if it is not deleted.
+// A duplicate key error says its asset_id is used by other crypto
+var RestoreById = func(ctx context.Context, coll IMCollection, id primitive.ObjectID) (crypto models.CryptoCurrency, err error) {
+	ctx, end := startOperation(ctx, "restore_by_id", current.Collection)
+	defer end(&err)
+
+	update := bson.M{"$unset": bson.M{"deleted_at": ""}, "$set": bson.M{"deleted": false, "updated_at": time.Now()}, "$inc": bson.M{"version": 1}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = coll.FindOneAndUpdate(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}, update, opts).Decode(&crypto)
+
+	logger.DebugContext(ctx, id.Hex(), "Document restored...")
+	return crypto, err
 }
 
-var DeleteById = func(coll IMCollection, id primitive.ObjectID) (primitive.ObjectID, error) {
-	var deletedDocument bson.M
+// Returns the cryptos deleted before the time, they are removed by PurgeByIds
+var ListDeletedBefore = func(ctx context.Context, coll IMCollection, before time.Time) (result []models.CryptoCurrency, err error) {
+	ctx, end := startOperation(ctx, "list_deleted_before", current.Collection)
+	defer end(&err)
+
+	cursor, err := coll.Find(ctx, bson.M{"deleted_at": bson.M{"$lt": before}})
+	if err != nil {
+		return result, err
+	}
+
+	defer cursor.Close(ctx)
 
-	err := coll.FindOneAndDelete(context.Background(), bson.M{"_id": id}).Decode(&deletedDocument)
+	err = cursor.All(ctx, &result)
 
-	logger.Debug(id.Hex(), "Document deleted...")
-	return id, err
+	logger.DebugContext(ctx, "", "Returning "+strconv.Itoa(len(result))+" cryptos deleted before "+before.Format(time.RFC3339)+"...")
+	return result, err
+}
+
+// Removes for good the deleted cryptos and the documents of them in other collection (votes, price history),
+// the filter by deleted_at keeps a crypto restored in the meantime
+var PurgeByIds = func(ctx context.Context, coll IMCollection, related []IMCollection, ids []primitive.ObjectID) (purged int64, err error) {
+	ctx, end := startOperation(ctx, "purge_by_ids", current.Collection)
+	defer end(&err)
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result, err := coll.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": bson.M{"$ne": nil}})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, other := range related {
+		_, err = other.DeleteMany(ctx, bson.M{"crypto_id": bson.M{"$in": ids}})
+		if err != nil {
+			return result.DeletedCount, err
+		}
+	}
+
+	logger.DebugContext(ctx, "", "Purged "+strconv.FormatInt(result.DeletedCount, 10)+" cryptos...")
+	return result.DeletedCount, nil
 }
 
 var QueryToUpdate = func(crypto models.CryptoCurrency) (where bson.M, update bson.M, err error) {
@@ -130,17 +373,18 @@ var QueryToUpdate = func(crypto models.CryptoCurrency) (where bson.M, update bso
 	}
 
 	switch crypto.UpdateType {
-	case models.UpVote: // Increment votes
-		where = bson.M{"_id": bson.M{"$eq": crypto.Id}}
-		update = bson.M{"$inc": bson.M{"votes": 1}, "$set": bson.M{"updated_at": time.Now()}}
-
-	case models.DownVote: // Decrement votes only if votes > 0
-		where = bson.M{"_id": bson.M{"$eq": crypto.Id}, "votes": bson.M{"$gt": 0}}
-		update = bson.M{"$inc": bson.M{"votes": -1}, "$set": bson.M{"updated_at": time.Now()}}
+	case models.UpdateVotes: // Increment or decrement votes by the change of voter
+		where = bson.M{"_id": bson.M{"$eq": crypto.Id}, "deleted_at": nil}
+		update = bson.M{"$inc": bson.M{"votes": crypto.VotesDelta, "version": 1}, "$set": bson.M{"updated_at": time.Now()}}
 
 	default: // Trazer o UpdateOnly como default
-		where = bson.M{"_id": bson.M{"$eq": crypto.Id}}
-		update = bson.M{"$set": crypto.FieldsToUpdate()}
+		where = bson.M{"_id": bson.M{"$eq": crypto.Id}, "deleted_at": nil}
+		update = bson.M{"$set": crypto.FieldsToUpdate(), "$inc": bson.M{"version": 1}}
+
+		// Optimistic concurrency, other update in the meantime changed the version and nothing matches
+		if crypto.ExpectedVersion != nil {
+			where["version"] = versionFilter(*crypto.ExpectedVersion)
+		}
 	}
 
 	// Help to log
@@ -157,6 +401,62 @@ var QueryToUpdate = func(crypto models.CryptoCurrency) (where bson.M, update bso
 	return where, update, nil
 }
 
+// Cryptos saved before the version existed do not have the field, they are version 0
+func versionFilter(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": []interface{}{0, nil}}
+	}
+	return version
+}
+
+var QueryToSearch = func(search repositories.SearchParams) bson.M {
+	where := notDeleted()
+
+	// Name is case-insensitive, by substring or by prefix
+	if search.Name != "" {
+		pattern := regexp.QuoteMeta(search.Name)
+		if search.NamePrefix {
+			pattern = "^" + pattern
+		}
+		where["name"] = bson.M{"$regex": pattern, "$options": "i"}
+	}
+
+	if search.AssetId != "" {
+		where["asset_id"] = strings.ToUpper(search.AssetId)
+	}
+
+	price := bson.M{}
+	if search.MinPrice != nil {
+		price["$gte"] = *search.MinPrice
+	}
+	if search.MaxPrice != nil {
+		price["$lte"] = *search.MaxPrice
+	}
+	if len(price) > 0 {
+		where["price_usd"] = price
+	}
+
+	votes := bson.M{}
+	if search.MinVotes != nil {
+		votes["$gte"] = *search.MinVotes
+	}
+	if search.MaxVotes != nil {
+		votes["$lte"] = *search.MaxVotes
+	}
+	if len(votes) > 0 {
+		where["votes"] = votes
+	}
+
+	// Help to log
+	whereLog, err := json.Marshal(where)
+	if err != nil {
+		logger.Error("", err.Error())
+	}
+	logger.Debug("", "Query to search selected - where: "+string(whereLog))
+
+	return where
+}
+
 var OrderBy = func(sort repositories.SortParams) (string, int) {
 	field := selectField(sort.Field)
 	// default desc
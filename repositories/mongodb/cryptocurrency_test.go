@@ -0,0 +1,127 @@
+package mongodb
+
+import (
+	"api-desafio-kvr/models"
+	"api-desafio-kvr/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Collection of cryptos that finds the documents and keeps the filter and limit received, other methods are not used
+type mockCryptosCollection struct {
+	IMCollection
+	documents []interface{}
+	filter    interface{}
+	limit     *int64
+}
+
+func (m *mockCryptosCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	m.filter = filter
+	m.limit = options.MergeFindOptions(opts...).Limit
+	return mongo.NewCursorFromDocuments(m.documents, nil, nil)
+}
+
+// Testing version 0 matches the cryptos saved before the version existed, with null or without the field
+func TestVersionFilter(t *testing.T) {
+	require.Equal(t, bson.M{"$in": []interface{}{0, nil}}, versionFilter(0))
+	require.Equal(t, int64(3), versionFilter(3))
+}
+
+// Testing update without type is an error
+func TestQueryToUpdateWithoutType(t *testing.T) {
+	_, _, err := QueryToUpdate(models.CryptoCurrency{Id: primitive.NewObjectID()})
+
+	require.NotNil(t, err)
+	require.Equal(t, "updateType is empty", err.Error())
+}
+
+// Testing update of votes increments votes and version of the crypto not deleted, of any version
+func TestQueryToUpdateWithVotes(t *testing.T) {
+	expected := int64(2)
+	crypto := models.CryptoCurrency{Id: primitive.NewObjectID(), UpdateType: models.UpdateVotes, VotesDelta: -1, ExpectedVersion: &expected}
+
+	where, update, err := QueryToUpdate(crypto)
+
+	require.Nil(t, err)
+	require.Equal(t, bson.M{"_id": bson.M{"$eq": crypto.Id}, "deleted_at": nil}, where)
+	require.Equal(t, bson.M{"votes": int32(-1), "version": 1}, update["$inc"])
+	require.IsType(t, time.Time{}, update["$set"].(bson.M)["updated_at"])
+}
+
+// Testing edit sets the fields and filters by the expected version, legacy cryptos when it is 0
+func TestQueryToUpdateWithExpectedVersion(t *testing.T) {
+	tests := []struct {
+		expected *int64
+		filter   interface{}
+	}{
+		{nil, nil},
+		{new(int64), bson.M{"$in": []interface{}{0, nil}}},
+		{func() *int64 { v := int64(4); return &v }(), int64(4)},
+	}
+
+	for _, test := range tests {
+		crypto := models.CryptoCurrency{
+			Id:              primitive.NewObjectID(),
+			Name:            "Bitcoin",
+			AssetId:         "BTC",
+			PriceUsd:        10.5,
+			UpdateType:      models.UpdateOnly,
+			ExpectedVersion: test.expected,
+		}
+
+		where, update, err := QueryToUpdate(crypto)
+		require.Nil(t, err)
+
+		require.Equal(t, bson.M{"$eq": crypto.Id}, where["_id"])
+		require.Contains(t, where, "deleted_at")
+		require.Nil(t, where["deleted_at"])
+		version, filtered := where["version"]
+		require.Equal(t, test.expected != nil, filtered)
+		require.Equal(t, test.filter, version)
+
+		set := update["$set"].(bson.M)
+		require.Equal(t, "Bitcoin", set["name"])
+		require.Equal(t, "BTC", set["asset_id"])
+		require.Equal(t, 10.5, set["price_usd"])
+		require.Equal(t, bson.M{"version": 1}, update["$inc"])
+	}
+}
+
+// Testing search finds one more than page size, returns the token of last crypto and filters by search and token
+func TestSearchWithPage(t *testing.T) {
+	cryptos := returnMockPageCryptos()[:3]
+	documents := []interface{}{}
+	for _, crypto := range cryptos {
+		documents = append(documents, crypto)
+	}
+	search := repositories.SearchParams{Name: "bit"}
+	sort := repositories.SortParams{Field: "votes", Asc: true}
+
+	coll := &mockCryptosCollection{documents: documents}
+	result, nextToken, err := Search(context.Background(), coll, search, sort, repositories.PageParams{Size: 2})
+	require.Nil(t, err)
+	require.Equal(t, cryptos[:2], result)
+	require.Equal(t, int64(3), *coll.limit)
+	require.Equal(t, QueryToSearch(search), coll.filter)
+
+	coll = &mockCryptosCollection{documents: documents[2:]}
+	result, next, err := Search(context.Background(), coll, search, sort, repositories.PageParams{Size: 2, Token: nextToken})
+	require.Nil(t, err)
+	require.Equal(t, cryptos[2:], result)
+	require.Empty(t, next)
+
+	filter := coll.filter.(bson.M)
+	require.Equal(t, bson.M{"$regex": "bit", "$options": "i"}, filter["name"])
+	require.Contains(t, filter, "deleted_at")
+	require.Contains(t, filter, "$or")
+
+	_, _, err = Search(context.Background(), coll, search, sort, repositories.PageParams{Size: 2, Token: "invalid"})
+	require.Equal(t, ErrInvalidPageToken, err)
+}
@@ -0,0 +1,104 @@
+package mongodb
+
+import (
+	"api-desafio-kvr/models"
+	"api-desafio-kvr/repositories"
+	"encoding/base64"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidPageToken = errors.New("page_token is invalid")
+
+// The token keeps the sort and the last document of page, the next page starts
+// after it. Ties in the sorted field are broken by _id, so the token is stable.
+type pageToken struct {
+	Field string             `bson:"f"`
+	Asc   bool               `bson:"a"`
+	Value interface{}        `bson:"v"`
+	Id    primitive.ObjectID `bson:"i"`
+}
+
+func encodePageToken(field string, asc bool, last models.CryptoCurrency) (string, error) {
+	return encodeToken(pageToken{
+		Field: field,
+		Asc:   asc,
+		Value: sortValue(field, last),
+		Id:    last.Id,
+	})
+}
+
+func encodeToken(token pageToken) (string, error) {
+	byteToken, err := bson.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(byteToken), nil
+}
+
+func decodePageToken(encoded string, field string, asc bool) (pageToken, error) {
+	token := pageToken{}
+
+	byteToken, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return token, ErrInvalidPageToken
+	}
+
+	err = bson.Unmarshal(byteToken, &token)
+	if err != nil {
+		return token, ErrInvalidPageToken
+	}
+
+	// token created with other sort is not valid to this query
+	if token.Field != field || token.Asc != asc {
+		return token, ErrInvalidPageToken
+	}
+
+	return token, nil
+}
+
+func sortValue(field string, crypto models.CryptoCurrency) interface{} {
+	switch field {
+	case "votes":
+		return crypto.Votes
+	case "price_usd":
+		return crypto.PriceUsd
+	default:
+		return crypto.Name
+	}
+}
+
+// Filter to documents after the token in the sort order
+func afterPageToken(token pageToken) bson.M {
+	operator := "$lt"
+	if token.Asc {
+		operator = "$gt"
+	}
+
+	return bson.M{"$or": bson.A{
+		bson.M{token.Field: bson.M{operator: token.Value}},
+		bson.M{token.Field: token.Value, "_id": bson.M{operator: token.Id}},
+	}}
+}
+
+// Sort by field and by _id to untie
+func pageSort(field string, order int) bson.D {
+	return bson.D{{Key: field, Value: order}, {Key: "_id", Value: order}}
+}
+
+func pageFilter(sort repositories.SortParams, page repositories.PageParams) (bson.M, error) {
+	if page.Token == "" {
+		return bson.M{}, nil
+	}
+
+	field, _ := OrderBy(sort)
+	token, err := decodePageToken(page.Token, field, sort.Asc)
+	if err != nil {
+		return bson.M{}, err
+	}
+
+	return afterPageToken(token), nil
+}
@@ -0,0 +1,217 @@
+package mongodb
+
+import (
+	"api-desafio-kvr/models"
+	"api-desafio-kvr/repositories"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var pageFields = []string{"name", "votes", "price_usd"}
+
+// Help function to cryptos with ties in every field, the ids are in order of creation
+func returnMockPageCryptos() []models.CryptoCurrency {
+	cryptos := []models.CryptoCurrency{}
+	for i := 0; i < 7; i++ {
+		cryptos = append(cryptos, models.CryptoCurrency{
+			Id:       primitive.NewObjectID(),
+			Name:     []string{"Bitcoin", "Ethereum"}[i%2],
+			Votes:    int32(i % 3),
+			PriceUsd: float64(i%2) * 10.5,
+		})
+	}
+	return cryptos
+}
+
+// Help function to compare the values of page, string, int32, float64 and ObjectID
+func comparePageValue(a interface{}, b interface{}) int {
+	switch value := a.(type) {
+	case string:
+		other := b.(string)
+		switch {
+		case value < other:
+			return -1
+		case value > other:
+			return 1
+		}
+		return 0
+	case int32:
+		return comparePageValue(float64(value), float64(b.(int32)))
+	case float64:
+		other := b.(float64)
+		switch {
+		case value < other:
+			return -1
+		case value > other:
+			return 1
+		}
+		return 0
+	case primitive.ObjectID:
+		return comparePageValue(value.Hex(), b.(primitive.ObjectID).Hex())
+	}
+	panic(fmt.Sprintf("value of page not expected: %T", a))
+}
+
+// Help function to apply in memory the filter of afterPageToken, with $or, $gt, $lt and equality
+func matchesPageFilter(filter bson.M, crypto models.CryptoCurrency) bool {
+	for key, condition := range filter {
+		if key == "$or" {
+			matched := false
+			for _, clause := range condition.(bson.A) {
+				matched = matched || matchesPageFilter(clause.(bson.M), crypto)
+			}
+			if !matched {
+				return false
+			}
+			continue
+		}
+
+		var value interface{} = crypto.Id
+		if key != "_id" {
+			value = sortValue(key, crypto)
+		}
+
+		operators, ok := condition.(bson.M)
+		if !ok {
+			if comparePageValue(value, condition) != 0 {
+				return false
+			}
+			continue
+		}
+		for operator, bound := range operators {
+			result := comparePageValue(value, bound)
+			if operator == "$gt" && result <= 0 || operator == "$lt" && result >= 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Testing the token decodes to the sort and the last document of page
+func TestPageTokenRoundTrip(t *testing.T) {
+	last := models.CryptoCurrency{Id: primitive.NewObjectID(), Name: "Bitcoin", Votes: 7, PriceUsd: 21.5}
+	expected := map[string]interface{}{"name": "Bitcoin", "votes": int32(7), "price_usd": 21.5}
+
+	for _, field := range pageFields {
+		for _, asc := range []bool{true, false} {
+			encoded, err := encodePageToken(field, asc, last)
+			require.Nil(t, err)
+
+			token, err := decodePageToken(encoded, field, asc)
+			require.Nil(t, err, field)
+			require.Equal(t, field, token.Field)
+			require.Equal(t, asc, token.Asc)
+			require.Equal(t, expected[field], token.Value, field)
+			require.Equal(t, last.Id, token.Id)
+		}
+	}
+}
+
+// Testing malformed, tampered and of other sort tokens are ErrInvalidPageToken
+func TestDecodePageTokenInvalid(t *testing.T) {
+	valid, err := encodePageToken("votes", true, models.CryptoCurrency{Id: primitive.NewObjectID(), Votes: 1})
+	require.Nil(t, err)
+
+	raw, err := base64.RawURLEncoding.DecodeString(valid)
+	require.Nil(t, err)
+	tampered := append([]byte{}, raw...)
+	tampered[0] = 0xFF
+
+	notBson := base64.RawURLEncoding.EncodeToString([]byte("not bson"))
+
+	tests := []struct {
+		name  string
+		token string
+		field string
+		asc   bool
+	}{
+		{"not base64", "%%%", "votes", true},
+		{"padded base64", base64.URLEncoding.EncodeToString(raw), "votes", true},
+		{"not bson", notBson, "votes", true},
+		{"length tampered", base64.RawURLEncoding.EncodeToString(tampered), "votes", true},
+		{"truncated", base64.RawURLEncoding.EncodeToString(raw[:len(raw)-3]), "votes", true},
+		{"other field", valid, "name", true},
+		{"other order", valid, "votes", false},
+	}
+
+	for _, test := range tests {
+		_, err := decodePageToken(test.token, test.field, test.asc)
+		require.Equal(t, ErrInvalidPageToken, err, test.name)
+	}
+
+	_, err = pageFilter(repositories.SortParams{Field: "votes", Asc: true}, repositories.PageParams{Size: 2, Token: notBson})
+	require.Equal(t, ErrInvalidPageToken, err)
+}
+
+// Testing the filter of token takes the greater (asc) or lesser (desc) values and the ties after _id
+func TestAfterPageToken(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	for _, field := range pageFields {
+		for _, test := range []struct {
+			asc      bool
+			operator string
+		}{{true, "$gt"}, {false, "$lt"}} {
+			value := sortValue(field, models.CryptoCurrency{Name: "Bitcoin", Votes: 3, PriceUsd: 1.5})
+			filter := afterPageToken(pageToken{Field: field, Asc: test.asc, Value: value, Id: id})
+
+			expected := bson.M{"$or": bson.A{
+				bson.M{field: bson.M{test.operator: value}},
+				bson.M{field: value, "_id": bson.M{test.operator: id}},
+			}}
+			require.Equal(t, expected, filter, field+" "+test.operator)
+		}
+	}
+}
+
+// Testing pages read with the token have each crypto once and in order, with ties in the field
+func TestAfterPageTokenWithTies(t *testing.T) {
+	cryptos := returnMockPageCryptos()
+
+	for _, field := range pageFields {
+		for _, asc := range []bool{true, false} {
+			// order of pageSort: field and then _id
+			expected := append([]models.CryptoCurrency{}, cryptos...)
+			sort.Slice(expected, func(i, j int) bool {
+				result := comparePageValue(sortValue(field, expected[i]), sortValue(field, expected[j]))
+				if result == 0 {
+					result = comparePageValue(expected[i].Id, expected[j].Id)
+				}
+				if asc {
+					return result < 0
+				}
+				return result > 0
+			})
+
+			read := []models.CryptoCurrency{}
+			params := repositories.PageParams{Size: 2}
+			for pages := 0; pages <= len(cryptos); pages++ {
+				filter, err := pageFilter(repositories.SortParams{Field: field, Asc: asc}, params)
+				require.Nil(t, err)
+
+				page := []models.CryptoCurrency{}
+				for _, crypto := range expected {
+					if matchesPageFilter(filter, crypto) && len(page) < int(params.Size) {
+						page = append(page, crypto)
+					}
+				}
+				read = append(read, page...)
+				if len(page) < int(params.Size) {
+					break
+				}
+
+				params.Token, err = encodePageToken(field, asc, page[len(page)-1])
+				require.Nil(t, err)
+			}
+
+			require.Equal(t, expected, read, field)
+		}
+	}
+}
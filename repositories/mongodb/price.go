@@ -0,0 +1,191 @@
+package mongodb
+
+import (
+	"api-desafio-kvr/models"
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const PRICES_COLLECTION = "price_history"
+
+// Max of points (raw) or buckets returned by ListPriceBuckets
+const MaxPriceBuckets = 10000
+
+var ErrTooManyPrices = errors.New("time range has more than " + strconv.Itoa(MaxPriceBuckets) + " prices, use a shorter range or a greater interval")
+
+// Index of versions before the unique one, with the same keys
+const oldPricesIndex = "crypto_time"
+
+func GetPricesCollection(client *mongo.Client) *mongo.Collection {
+	return client.Database(current.Database).Collection(current.PricesCollection)
+}
+
+// History of a crypto is read by time range, one point by time so RecordPrice upserts it
+func CreatePricesIndexes(coll *mongo.Collection) error {
+	ctx := context.Background()
+
+	// same keys of the new index, it is removed before
+	_, err := coll.Indexes().DropOne(ctx, oldPricesIndex)
+	if err != nil && !isIndexNotFound(err) {
+		return err
+	}
+
+	index := mongo.IndexModel{
+		Keys:    primitive.D{{Key: "crypto_id", Value: 1}, {Key: "time", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("crypto_time_unique"),
+	}
+
+	_, err = coll.Indexes().CreateOne(ctx, index)
+	if err != nil {
+		// points of old versions in the same time, the history is still read by the index
+		index.Options = options.Index().SetName(oldPricesIndex)
+		_, _ = coll.Indexes().CreateOne(ctx, index)
+		return err
+	}
+
+	logger.Debug("", "Indexes of "+current.PricesCollection+" created...")
+	return err
+}
+
+// Saves the price of crypto when it is different of the last one saved, returns if it was saved.
+// The point is upserted by crypto and time, so the same write saves it once. The check of change is
+// best-effort without transactions: two writes of the same price may both read the old last one
+var RecordPrice = func(ctx context.Context, coll IMCollection, cryptoId primitive.ObjectID, price float64, at time.Time) (recorded bool, err error) {
+	ctx, end := startOperation(ctx, "record_price", current.PricesCollection)
+	defer end(&err)
+	var last models.PricePoint
+
+	opts := options.FindOne().SetSort(primitive.D{{Key: "time", Value: -1}})
+	err = coll.FindOne(ctx, bson.M{"crypto_id": cryptoId}, opts).Decode(&last)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return false, err
+	}
+	if err == nil && last.PriceUsd == price {
+		return false, nil
+	}
+
+	filter := bson.M{"crypto_id": cryptoId, "time": at}
+	update := bson.M{"$setOnInsert": bson.M{"_id": primitive.NewObjectID(), "price_usd": price}}
+	result, err := coll.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	// other write upserted the point at the same time
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if result.UpsertedCount == 0 {
+		return false, nil
+	}
+
+	logger.DebugContext(ctx, cryptoId.Hex(), "Price "+strconv.FormatFloat(price, 'f', -1, 64)+" recorded...")
+	return true, nil
+}
+
+// Returns the prices of crypto in [from, to) grouped by interval, interval 0 returns each point (raw).
+// The last price before from opens the first bucket. ErrTooManyPrices when there are more than MaxPriceBuckets
+var ListPriceBuckets = func(ctx context.Context, coll IMCollection, cryptoId primitive.ObjectID, from time.Time, to time.Time, interval time.Duration) (result []models.PriceBucket, err error) {
+	ctx, end := startOperation(ctx, "list_price_buckets", current.PricesCollection)
+	defer end(&err)
+	match := bson.M{"crypto_id": cryptoId, "time": bson.M{"$gte": from, "$lt": to}}
+
+	if interval <= 0 {
+		var points []models.PricePoint
+		opts := options.Find().SetSort(primitive.D{{Key: "time", Value: 1}}).SetLimit(MaxPriceBuckets + 1)
+		var cursor *mongo.Cursor
+		cursor, err = coll.Find(ctx, match, opts)
+		if err != nil {
+			return result, err
+		}
+		defer cursor.Close(ctx)
+
+		err = cursor.All(ctx, &points)
+		if err != nil {
+			return result, err
+		}
+		if len(points) > MaxPriceBuckets {
+			return result, ErrTooManyPrices
+		}
+
+		result = make([]models.PriceBucket, 0, len(points))
+		for _, point := range points {
+			result = append(result, point.ToBucket())
+		}
+		return result, nil
+	}
+
+	// Start of bucket is the time truncated to interval (in UTC), the points are sorted to $first and $last
+	millis := interval.Milliseconds()
+	start := bson.M{"$toDate": bson.M{"$subtract": []interface{}{
+		bson.M{"$toLong": "$time"},
+		bson.M{"$mod": []interface{}{bson.M{"$toLong": "$time"}, millis}},
+	}}}
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$sort": primitive.D{{Key: "time", Value: 1}}},
+		{"$group": bson.M{
+			"_id":   start,
+			"open":  bson.M{"$first": "$price_usd"},
+			"high":  bson.M{"$max": "$price_usd"},
+			"low":   bson.M{"$min": "$price_usd"},
+			"close": bson.M{"$last": "$price_usd"},
+			"count": bson.M{"$sum": 1},
+		}},
+		{"$sort": primitive.D{{Key: "_id", Value: 1}}},
+		{"$limit": MaxPriceBuckets + 1},
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return result, err
+	}
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &result)
+	if err != nil {
+		return result, err
+	}
+	if len(result) > MaxPriceBuckets {
+		return nil, ErrTooManyPrices
+	}
+
+	var last models.PricePoint
+	opts := options.FindOne().SetSort(primitive.D{{Key: "time", Value: -1}})
+	err = coll.FindOne(ctx, bson.M{"crypto_id": cryptoId, "time": bson.M{"$lt": from}}, opts).Decode(&last)
+	if err == mongo.ErrNoDocuments {
+		err = nil
+	} else if err == nil {
+		result = seedBuckets(result, last.PriceUsd, bucketStart(from, interval))
+	}
+
+	logger.DebugContext(ctx, cryptoId.Hex(), "Returning "+strconv.Itoa(len(result))+" price buckets...")
+	return result, err
+}
+
+// Start of the bucket of t, as the $mod of pipeline
+func bucketStart(t time.Time, interval time.Duration) time.Time {
+	millis := t.UnixMilli()
+	return time.UnixMilli(millis - millis%interval.Milliseconds()).UTC()
+}
+
+// The price before the range is the open of the bucket of from, without a change in it the bucket has count 0
+func seedBuckets(buckets []models.PriceBucket, price float64, start time.Time) []models.PriceBucket {
+	if len(buckets) > 0 && buckets[0].Start.Equal(start) {
+		first := &buckets[0]
+		first.Open = price
+		first.High = math.Max(first.High, price)
+		first.Low = math.Min(first.Low, price)
+		return buckets
+	}
+
+	seeded := models.PriceBucket{Start: start, Open: price, High: price, Low: price, Close: price}
+	return append([]models.PriceBucket{seeded}, buckets...)
+}
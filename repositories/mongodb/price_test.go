@@ -0,0 +1,103 @@
+package mongodb
+
+import (
+	"api-desafio-kvr/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Collection of prices with the last point and the result of upsert, other methods are not used
+type mockPricesCollection struct {
+	IMCollection
+	last      *models.PricePoint
+	upsertErr error
+	upserted  int64
+	filter    interface{}
+}
+
+func (m *mockPricesCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	if m.last == nil {
+		return mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+	}
+	return mongo.NewSingleResultFromDocument(m.last, nil, nil)
+}
+
+func (m *mockPricesCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	m.filter = filter
+	if m.upsertErr != nil {
+		return nil, m.upsertErr
+	}
+	return &mongo.UpdateResult{UpsertedCount: m.upserted}, nil
+}
+
+// Testing the price is upserted by crypto and time only when it changed
+func TestRecordPrice(t *testing.T) {
+	cryptoId := primitive.NewObjectID()
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	coll := &mockPricesCollection{upserted: 1}
+	recorded, err := RecordPrice(context.Background(), coll, cryptoId, 2.5, at)
+	require.Nil(t, err)
+	require.True(t, recorded)
+	require.Equal(t, bson.M{"crypto_id": cryptoId, "time": at}, coll.filter)
+
+	coll = &mockPricesCollection{last: &models.PricePoint{CryptoId: cryptoId, PriceUsd: 2.5, Time: at.Add(-time.Hour)}}
+	recorded, err = RecordPrice(context.Background(), coll, cryptoId, 2.5, at)
+	require.Nil(t, err)
+	require.False(t, recorded)
+	require.Nil(t, coll.filter)
+
+	// point of the same time saved before
+	coll = &mockPricesCollection{upserted: 0}
+	recorded, err = RecordPrice(context.Background(), coll, cryptoId, 3, at)
+	require.Nil(t, err)
+	require.False(t, recorded)
+
+	// point of the same time saved by other write meanwhile
+	duplicate := mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000 duplicate key error"}}}
+	coll = &mockPricesCollection{upsertErr: duplicate}
+	recorded, err = RecordPrice(context.Background(), coll, cryptoId, 3, at)
+	require.Nil(t, err)
+	require.False(t, recorded)
+
+	coll = &mockPricesCollection{upsertErr: errors.New("some error")}
+	_, err = RecordPrice(context.Background(), coll, cryptoId, 3, at)
+	require.NotNil(t, err)
+}
+
+// Testing start of bucket is the time truncated to interval in UTC
+func TestBucketStart(t *testing.T) {
+	from := time.Date(2024, 5, 1, 10, 30, 15, 0, time.FixedZone("BRT", -3*3600))
+
+	require.Equal(t, time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC), bucketStart(from, time.Hour))
+	require.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), bucketStart(from, 24*time.Hour))
+}
+
+// Testing the price before the range opens the first bucket or is a bucket without change
+func TestSeedBuckets(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	buckets := seedBuckets([]models.PriceBucket{
+		{Start: start, Open: 2, High: 3, Low: 2, Close: 3, Count: 2},
+		{Start: start.Add(time.Hour), Open: 4, High: 4, Low: 4, Close: 4, Count: 1},
+	}, 1, start)
+	require.Len(t, buckets, 2)
+	require.Equal(t, models.PriceBucket{Start: start, Open: 1, High: 3, Low: 1, Close: 3, Count: 2}, buckets[0])
+
+	buckets = seedBuckets([]models.PriceBucket{
+		{Start: start.Add(time.Hour), Open: 4, High: 4, Low: 4, Close: 4, Count: 1},
+	}, 1, start)
+	require.Len(t, buckets, 2)
+	require.Equal(t, models.PriceBucket{Start: start, Open: 1, High: 1, Low: 1, Close: 1}, buckets[0])
+
+	buckets = seedBuckets(nil, 5, start)
+	require.Equal(t, []models.PriceBucket{{Start: start, Open: 5, High: 5, Low: 5, Close: 5}}, buckets)
+}
@@ -0,0 +1,42 @@
+package mongodb
+
+import (
+	"api-desafio-kvr/metrics"
+	"api-desafio-kvr/tracing"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("api-desafio-kvr/repositories/mongodb")
+
+// startOperation starts the span of one operation in mongodb, the returned func ends it
+// and observes the latency: ctx, end := startOperation(ctx, "get_by_id", collection); defer end(&err)
+func startOperation(ctx context.Context, operation string, collection string) (context.Context, func(*error)) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, "mongodb."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemMongoDB,
+			semconv.DBNameKey.String(current.Database),
+			semconv.DBMongoDBCollectionKey.String(collection),
+			semconv.DBOperationKey.String(operation),
+		),
+	)
+
+	return ctx, func(err *error) {
+		metrics.ObserveMongo(operation, start)
+
+		// not found is an answer, not a failure of database
+		if err == nil || errors.Is(*err, mongo.ErrNoDocuments) {
+			tracing.End(span, nil)
+			return
+		}
+		tracing.End(span, *err)
+	}
+}
@@ -0,0 +1,28 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WithTransaction runs fn in a transaction of the client of coll, the writes of fn with its ctx are
+// committed together or none. fn can run again when the transaction has a transient error.
+// Transactions need a replica set, with MONGODB_TRANSACTIONS=false fn runs without them
+var WithTransaction = func(ctx context.Context, coll *mongo.Collection, fn func(ctx context.Context) error) error {
+	if !current.Transactions {
+		return fn(ctx)
+	}
+
+	return coll.Database().Client().UseSession(ctx, func(session mongo.SessionContext) error {
+		_, err := session.WithTransaction(session, func(txCtx mongo.SessionContext) (interface{}, error) {
+			return nil, fn(txCtx)
+		})
+		return err
+	})
+}
+
+// InTransaction says if ctx is of WithTransaction, out of it the caller undoes its writes by hand
+func InTransaction(ctx context.Context) bool {
+	return mongo.SessionFromContext(ctx) != nil
+}
@@ -0,0 +1,59 @@
+package mongodb
+
+import (
+	"api-desafio-kvr/models"
+	"context"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const VOTES_COLLECTION = "votes"
+
+func GetVotesCollection(client *mongo.Client) *mongo.Collection {
+	return client.Database(current.Database).Collection(current.VotesCollection)
+}
+
+// One vote by voter in each crypto
+func CreateVotesIndexes(coll *mongo.Collection) error {
+	index := mongo.IndexModel{
+		Keys:    primitive.D{{Key: "voter_id", Value: 1}, {Key: "crypto_id", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("voter_crypto_unique"),
+	}
+
+	_, err := coll.Indexes().CreateOne(context.Background(), index)
+
+	logger.Debug("", "Indexes of "+current.VotesCollection+" created...")
+	return err
+}
+
+// Saves the current vote of voter and returns the vote before it, VoteNone if voter never voted
+var SetVote = func(ctx context.Context, coll IMCollection, voterId string, cryptoId primitive.ObjectID, value int32) (_ int32, err error) {
+	ctx, end := startOperation(ctx, "set_vote", current.VotesCollection)
+	defer end(&err)
+	var previous models.Vote
+
+	filter := bson.M{"voter_id": voterId, "crypto_id": cryptoId}
+	update := bson.M{
+		"$set":         bson.M{"value": value, "updated_at": time.Now()},
+		"$setOnInsert": bson.M{"_id": primitive.NewObjectID(), "created_at": time.Now()},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before)
+
+	err = coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&previous)
+	// Concurrent first votes of same voter, the other one inserted then update it
+	if mongo.IsDuplicateKeyError(err) {
+		err = coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&previous)
+	}
+	if err == mongo.ErrNoDocuments {
+		previous.Value = models.VoteNone
+		err = nil
+	}
+
+	logger.DebugContext(ctx, cryptoId.Hex(), "Vote of "+voterId+" changed from "+strconv.Itoa(int(previous.Value))+" to "+strconv.Itoa(int(value))+"...")
+	return previous.Value, err
+}
@@ -0,0 +1,96 @@
+package redis
+
+import (
+	"api-desafio-kvr/models"
+	"api-desafio-kvr/repositories/cache"
+	"context"
+	"encoding/json"
+
+	"github.com/go-redis/redis"
+)
+
+func (c *Client) Get(ctx context.Context, key string) []byte {
+	result, err := c.lookup(ctx, key)
+	if err != nil {
+		logger.ErrorContext(ctx, key, err.Error())
+	}
+
+	return result
+}
+
+// lookup separates the miss (nil, nil) of errors of redis
+func (c *Client) lookup(ctx context.Context, key string) (_ []byte, err error) {
+	logger.DebugContext(ctx, nameLog, "Getting cache for key: "+key)
+	ctx, end := startCommand(ctx, "GET")
+	defer end(&err)
+
+	result, err := c.rdb.WithContext(ctx).Get(key).Result()
+	if err == redis.Nil || (err == nil && result == "") {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(result), nil
+}
+
+func (c *Client) Remember(ctx context.Context, key string, load cache.Load) ([]byte, error) {
+	return cache.RememberWith(ctx, &c.loads, key, c.lookup, c.set, load)
+}
+
+func (c *Client) Set(ctx context.Context, key string, crypto models.CryptoCurrency, deleteAll bool) error {
+	byteValue, err := json.Marshal(crypto)
+	if err != nil {
+		logger.ErrorContext(ctx, crypto.Id.Hex(), "Error in response: "+err.Error())
+		return err
+	}
+
+	return c.SetByByte(ctx, key, string(byteValue), deleteAll)
+}
+
+func (c *Client) SetByByte(ctx context.Context, key string, value string, deleteAll bool) error {
+	c.loads.Invalidate()
+	err := c.set(ctx, key, []byte(value))
+
+	if deleteAll {
+		err = c.DeleteAll(ctx)
+	}
+
+	return err
+}
+
+func (c *Client) set(ctx context.Context, key string, value []byte) (err error) {
+	logger.DebugContext(ctx, nameLog, "Setting cache for key: "+key)
+	ctx, end := startCommand(ctx, "SET")
+	defer end(&err)
+
+	return c.rdb.WithContext(ctx).Set(key, value, c.ttl.For(key)).Err()
+}
+
+func (c *Client) Del(ctx context.Context, key string) (err error) {
+	logger.DebugContext(ctx, nameLog, "Deleting cache for key: "+key)
+	c.loads.Invalidate()
+	ctx, end := startCommand(ctx, "DEL")
+	defer end(&err)
+
+	return c.rdb.WithContext(ctx).Del(key).Err()
+}
+
+func (c *Client) DeleteAll(ctx context.Context) (err error) {
+	logger.DebugContext(ctx, nameLog, "Deleting cache for "+cache.PrefixDeleteAll)
+	c.loads.Invalidate()
+	ctx, end := startCommand(ctx, "SCAN")
+	defer end(&err)
+
+	rdb := c.rdb.WithContext(ctx)
+	iter := rdb.Scan(0, cache.PrefixDeleteAll+"*", 0).Iterator()
+	for iter.Next() {
+		err := rdb.Del(iter.Val()).Err()
+		if err != nil {
+			logger.ErrorContext(ctx, nameLog, err.Error())
+		}
+	}
+
+	return iter.Err()
+}
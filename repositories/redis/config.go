@@ -2,101 +2,103 @@ package redis
 
 import (
 	"api-desafio-kvr/helpers"
-	"api-desafio-kvr/models"
-	"encoding/json"
+	"api-desafio-kvr/repositories/cache"
+	"time"
 
 	"github.com/go-redis/redis"
 )
 
 var logger = &helpers.Log{}
-var YesDeleteAll = true
-var NoDeleteAll = false
-var PrefixDeleteAll = "ListAll"
 var nameLog = "REDIS"
 
-// Cache são criados em todas as operações do controller (exceto exclusao de crypto)
-// Toda vez que é realizada uma operação de criação/edição,
-// o cache de ListAll é apagado, evitando assim um cache desatualizado.
-
-func Connect() *redis.Client {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     "localhost:6379",
-		Password: "",
-		DB:       0,
-	})
-
-	return rdb
+type Config struct {
+	Addr         string           `json:"addr"`
+	Password     string           `json:"password"`
+	DB           int              `json:"db"`
+	PoolSize     int              `json:"pool_size"`
+	MinIdleConns int              `json:"min_idle_conns"`
+	DialTimeout  helpers.Duration `json:"dial_timeout"`
+	ReadTimeout  helpers.Duration `json:"read_timeout"`
+	WriteTimeout helpers.Duration `json:"write_timeout"`
+	PoolTimeout  helpers.Duration `json:"pool_timeout"`
 }
 
-func Get(key string) []byte {
-	logger.Debug(nameLog, "Getting cache for key: "+key)
-
-	client := Connect()
-	result, err := client.Get(key).Result()
-	if err != nil {
-		logger.Error(key, err.Error())
-	}
-
-	if result == "" {
-		return nil
+func DefaultConfig() Config {
+	return Config{
+		Addr:         "localhost:6379",
+		PoolSize:     20,
+		DialTimeout:  helpers.Duration{Duration: 5 * time.Second},
+		ReadTimeout:  helpers.Duration{Duration: 3 * time.Second},
+		WriteTimeout: helpers.Duration{Duration: 3 * time.Second},
+		PoolTimeout:  helpers.Duration{Duration: 4 * time.Second},
 	}
-
-	return []byte(result)
 }
 
-func Set(key string, crypto models.CryptoCurrency, deleteAll bool) error {
-	logger.Debug(nameLog, "Setting cache for key: "+key)
+// LoadConfig reads section "redis" of CONFIG_FILE and the env vars REDIS_*
+func LoadConfig() (Config, error) {
+	cfg := DefaultConfig()
 
-	byteValue, err := json.Marshal(crypto)
+	err := helpers.LoadConfigFile("redis", &cfg)
 	if err != nil {
-		logger.Error(crypto.Id.Hex(), "Error in response: "+err.Error())
-		return err
+		return cfg, err
 	}
 
-	client := Connect()
-	err = client.Set(key, string(byteValue), 0).Err()
-
-	if deleteAll {
-		err = DeleteAll()
+	envs := map[string]interface{}{
+		"REDIS_ADDR":           &cfg.Addr,
+		"REDIS_PASSWORD":       &cfg.Password,
+		"REDIS_DB":             &cfg.DB,
+		"REDIS_POOL_SIZE":      &cfg.PoolSize,
+		"REDIS_MIN_IDLE_CONNS": &cfg.MinIdleConns,
+		"REDIS_DIAL_TIMEOUT":   &cfg.DialTimeout,
+		"REDIS_READ_TIMEOUT":   &cfg.ReadTimeout,
+		"REDIS_WRITE_TIMEOUT":  &cfg.WriteTimeout,
+		"REDIS_POOL_TIMEOUT":   &cfg.PoolTimeout,
 	}
-
-	return err
-}
-
-func SetByByte(key string, value string, deleteAll bool) error {
-	logger.Debug(nameLog, "Setting cache for key: "+key)
-	client := Connect()
-	err := client.Set(key, value, 0).Err()
-
-	if deleteAll {
-		err = DeleteAll()
+	for key, target := range envs {
+		err = helpers.SetFromEnv(key, target)
+		if err != nil {
+			return cfg, err
+		}
 	}
 
-	return err
+	return cfg, nil
 }
 
-func Del(key string) error {
-	logger.Debug(nameLog, "Deleting cache for key: "+key)
+// Client is the cache in redis, it uses the pool of connections shared by the application
+type Client struct {
+	rdb   *redis.Client
+	ttl   cache.TTL
+	loads cache.Loads
+}
 
-	client := Connect()
-	err := client.Del(key).Err()
+var _ cache.Cache = (*Client)(nil)
 
-	return err
+func NewCache(rdb *redis.Client, ttl cache.TTL) *Client {
+	return &Client{rdb: rdb, ttl: ttl}
 }
 
-func DeleteAll() error {
-	logger.Debug(nameLog, "Deleting cache for "+PrefixDeleteAll)
-
-	client := Connect()
-	iter := client.Scan(0, PrefixDeleteAll+"*", 0).Iterator()
-	for iter.Next() {
-		err := client.Del(iter.Val()).Err()
-		if err != nil {
-			logger.Error(nameLog, err.Error())
-		}
-	}
+// Connect returns the pool of connections to redis, it is created once in start of application,
+// shared by cache, rate limit and streams, and closed in the end
+func Connect(cfg Config) *redis.Client {
+	logger.Info(nameLog, "Starting redis client to "+cfg.Addr)
+	return redis.NewClient(&redis.Options{
+		Addr:         cfg.Addr,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		PoolSize:     cfg.PoolSize,
+		MinIdleConns: cfg.MinIdleConns,
+		DialTimeout:  cfg.DialTimeout.Duration,
+		ReadTimeout:  cfg.ReadTimeout.Duration,
+		WriteTimeout: cfg.WriteTimeout.Duration,
+		PoolTimeout:  cfg.PoolTimeout.Duration,
+	})
+}
 
-	err := iter.Err()
+func (c *Client) Ping() error {
+	return c.rdb.Ping().Err()
+}
 
-	return err
+// The pool is shared, it is closed by the owner
+func (c *Client) Close() error {
+	return nil
 }
@@ -0,0 +1,27 @@
+package redis
+
+import (
+	"api-desafio-kvr/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("api-desafio-kvr/repositories/redis")
+
+// startCommand starts the span of one command in redis, the returned func ends it
+func startCommand(ctx context.Context, command string) (context.Context, func(*error)) {
+	ctx, span := tracer.Start(ctx, "redis."+command,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemRedis,
+			semconv.DBOperationKey.String(command),
+		),
+	)
+
+	return ctx, func(err *error) {
+		tracing.End(span, *err)
+	}
+}
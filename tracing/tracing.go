@@ -0,0 +1,163 @@
+package tracing
+
+import (
+	"api-desafio-kvr/helpers"
+	"context"
+	"errors"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var logger = &helpers.Log{}
+var nameLog = "TRACING"
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"   // otlp over grpc to Endpoint
+	ExporterStdout = "stdout" // json in stdout, to local use
+	ExporterFile   = "file"   // json in File, to local use
+)
+
+type Config struct {
+	Exporter    string  `json:"exporter"`     // none, otlp, stdout or file
+	Endpoint    string  `json:"endpoint"`     // host:port of otlp collector
+	Insecure    bool    `json:"insecure"`     // otlp without tls
+	File        string  `json:"file"`         // path of exporter file
+	ServiceName string  `json:"service_name"` // service.name of spans
+	SampleRatio float64 `json:"sample_ratio"` // fraction of traces started here, parent sampled is kept
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Exporter:    ExporterNone,
+		Endpoint:    "localhost:4317",
+		Insecure:    true,
+		File:        "traces.json",
+		ServiceName: "api-desafio-kvr",
+		SampleRatio: 1,
+	}
+}
+
+// LoadConfig reads section "tracing" of CONFIG_FILE and the env vars TRACING_*
+func LoadConfig() (Config, error) {
+	cfg := DefaultConfig()
+
+	err := helpers.LoadConfigFile("tracing", &cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	envs := map[string]interface{}{
+		"TRACING_EXPORTER":     &cfg.Exporter,
+		"TRACING_ENDPOINT":     &cfg.Endpoint,
+		"TRACING_INSECURE":     &cfg.Insecure,
+		"TRACING_FILE":         &cfg.File,
+		"TRACING_SERVICE_NAME": &cfg.ServiceName,
+		"TRACING_SAMPLE_RATIO": &cfg.SampleRatio,
+	}
+	for key, target := range envs {
+		err = helpers.SetFromEnv(key, target)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	switch cfg.Exporter {
+	case ExporterNone, ExporterOTLP, ExporterStdout, ExporterFile:
+	default:
+		return cfg, errors.New("tracing exporter is invalid: " + cfg.Exporter)
+	}
+
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return cfg, errors.New("tracing sample ratio must be between 0 and 1")
+	}
+
+	return cfg, nil
+}
+
+// Setup sets the global tracer provider and the W3C propagation (traceparent and baggage),
+// the returned func sends the spans in buffer and must be called in the end of application.
+// With exporter none the spans are not recorded, but the context is still propagated.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if cfg.Exporter == ExporterNone {
+		logger.Info(nameLog, "Tracing is disabled")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closeOutput, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	logger.Info(nameLog, "Exporting traces to "+cfg.Exporter)
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeOutput != nil {
+			closeOutput.Close()
+		}
+		return err
+	}, nil
+}
+
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, io.Closer, error) {
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err := otlptracegrpc.New(ctx, opts...)
+		return exporter, nil, err
+
+	case ExporterFile:
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, nil, err
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		return exporter, file, err
+
+	default:
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		return exporter, nil, err
+	}
+}
+
+// End records err in span, when it is not nil, and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Testing W3C traceparent is read even with exporter none
+func TestSetupPropagation(t *testing.T) {
+	stop, err := Setup(context.Background(), DefaultConfig())
+	require.Nil(t, err)
+	defer stop(context.Background())
+
+	carrier := propagation.MapCarrier{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spanContext.TraceID().String())
+	require.True(t, spanContext.IsRemote())
+}
+
+// Testing end records error and status of span
+func TestEndWithError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	_, span := provider.Tracer("test").Start(context.Background(), "mongodb.get_by_id")
+	End(span, errors.New("connection refused"))
+	_, span = provider.Tracer("test").Start(context.Background(), "mongodb.get_by_asset")
+	End(span, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+	require.Equal(t, "connection refused", spans[0].Status().Description)
+	require.Len(t, spans[0].Events(), 1)
+	require.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+// Testing exporter not supported is refused
+func TestLoadConfigWithExporterInvalid(t *testing.T) {
+	t.Setenv("TRACING_EXPORTER", "zipkin")
+
+	_, err := LoadConfig()
+
+	require.NotNil(t, err)
+	require.Equal(t, "tracing exporter is invalid: zipkin", err.Error())
+}
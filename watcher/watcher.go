@@ -0,0 +1,223 @@
+package watcher
+
+import (
+	"api-desafio-kvr/helpers"
+	"api-desafio-kvr/repositories/mongodb"
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var logger = &helpers.Log{}
+var nameLog = "WATCHER"
+
+type Config struct {
+	Enabled  bool             `json:"enabled"`   // change streams need a replica set
+	Id       string           `json:"id"`        // name of replica, its resume token is saved by it
+	RetryMin helpers.Duration `json:"retry_min"` // wait before opening the stream again after an error
+	RetryMax helpers.Duration `json:"retry_max"` // the wait doubles until it
+}
+
+func DefaultConfig() Config {
+	hostname, _ := os.Hostname()
+
+	return Config{
+		Enabled:  true,
+		Id:       hostname,
+		RetryMin: helpers.Duration{Duration: time.Second},
+		RetryMax: helpers.Duration{Duration: 30 * time.Second},
+	}
+}
+
+// LoadConfig reads section "watcher" of CONFIG_FILE and the env vars WATCHER_*
+func LoadConfig() (Config, error) {
+	cfg := DefaultConfig()
+
+	err := helpers.LoadConfigFile("watcher", &cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	envs := map[string]interface{}{
+		"WATCHER_ENABLED":   &cfg.Enabled,
+		"WATCHER_ID":        &cfg.Id,
+		"WATCHER_RETRY_MIN": &cfg.RetryMin,
+		"WATCHER_RETRY_MAX": &cfg.RetryMax,
+	}
+	for key, target := range envs {
+		err = helpers.SetFromEnv(key, target)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	if cfg.Enabled && cfg.Id == "" {
+		return cfg, errors.New("watcher id can not be empty")
+	}
+
+	if cfg.RetryMin.Duration <= 0 || cfg.RetryMax.Duration < cfg.RetryMin.Duration {
+		return cfg, errors.New("watcher retry min must be positive and not greater than retry max")
+	}
+
+	return cfg, nil
+}
+
+// Handler applies one change of crypto, it is called in the order of changes
+type Handler func(ctx context.Context, change mongodb.CryptoChange)
+
+// State is called with true when the stream is opened and with false when it ends,
+// the changes made while it is closed are handled when it resumes
+type State func(open bool)
+
+// Watcher reads the change stream of cryptos, so the writes of other replicas and of
+// other tools (mongo-express) are seen too. Each replica runs its own watcher and saves
+// its own resume token, after a restart it continues from the last change handled.
+type Watcher struct {
+	config  Config
+	cryptos *mongo.Collection
+	tokens  *mongo.Collection
+	handle  Handler
+	state   State
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// state can be nil
+func New(cfg Config, cryptos *mongo.Collection, tokens *mongo.Collection, handle Handler, state State) *Watcher {
+	return &Watcher{
+		config:  cfg,
+		cryptos: cryptos,
+		tokens:  tokens,
+		handle:  handle,
+		state:   state,
+		done:    make(chan struct{}),
+	}
+}
+
+// Start runs the watcher until Stop
+func (w *Watcher) Start() {
+	logger.Info(nameLog, "Starting watcher of cryptos as "+w.config.Id)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	go func() {
+		defer close(w.done)
+		w.Run(ctx)
+	}()
+}
+
+// Stop closes the stream and waits the change in progress
+func (w *Watcher) Stop() {
+	if w.cancel == nil {
+		return
+	}
+
+	logger.Info(nameLog, "Stopping watcher of cryptos")
+	w.cancel()
+	<-w.done
+}
+
+// Run opens the stream again after each error until ctx is done
+func (w *Watcher) Run(ctx context.Context) {
+	delay := w.config.RetryMin.Duration
+
+	for {
+		opened, err := w.watch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if opened {
+			delay = w.config.RetryMin.Duration
+		}
+
+		if err == nil {
+			continue
+		}
+
+		if mongodb.IsResumeTokenLost(err) {
+			logger.Warn(nameLog, "Resume token of "+w.config.Id+" is not in oplog, watching from now: "+err.Error())
+			w.saveToken(ctx, nil)
+		} else {
+			logger.Error(nameLog, "Error in change stream of cryptos, retry in "+delay.String()+": "+err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > w.config.RetryMax.Duration {
+			delay = w.config.RetryMax.Duration
+		}
+	}
+}
+
+// Handles the changes until the stream ends, nil error is an invalidate and the stream can be opened again
+func (w *Watcher) watch(ctx context.Context) (opened bool, err error) {
+	token, err := mongodb.GetResumeToken(ctx, w.tokens, w.config.Id)
+	if err != nil {
+		return false, err
+	}
+
+	stream, err := mongodb.WatchCryptos(ctx, w.cryptos, token)
+	if err != nil {
+		return false, err
+	}
+	defer stream.Close(context.Background())
+
+	w.setState(true)
+	defer w.setState(false)
+
+	if token != nil {
+		logger.Info(nameLog, "Change stream of cryptos resumed")
+	} else {
+		logger.Info(nameLog, "Change stream of cryptos opened")
+	}
+
+	for stream.Next(ctx) {
+		var change mongodb.CryptoChange
+		err = stream.Decode(&change)
+		if err != nil {
+			return true, err
+		}
+
+		// collection dropped or renamed, the stream ends and it can not resume after it
+		if change.OperationType == "invalidate" {
+			logger.Warn(nameLog, "Change stream of cryptos invalidated, watching from now")
+			w.saveToken(ctx, nil)
+			return true, nil
+		}
+
+		w.handle(ctx, change)
+		w.saveToken(ctx, stream.ResumeToken())
+	}
+
+	err = stream.Err()
+	if err == nil && ctx.Err() == nil {
+		err = errors.New("change stream closed by server")
+	}
+	return true, err
+}
+
+func (w *Watcher) setState(open bool) {
+	if w.state != nil {
+		w.state(open)
+	}
+}
+
+// Error to save is only logged, the next change saves it again
+func (w *Watcher) saveToken(ctx context.Context, token bson.Raw) {
+	err := mongodb.SaveResumeToken(ctx, w.tokens, w.config.Id, token)
+	if err != nil && ctx.Err() == nil {
+		logger.Error(nameLog, "Error to save resume token: "+err.Error())
+	}
+}
@@ -0,0 +1,273 @@
+package watcher
+
+import (
+	"api-desafio-kvr/helpers"
+	"api-desafio-kvr/repositories/mongodb"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Stream of changes in memory, Next blocks after the last change until ctx is done
+type mockStream struct {
+	changes []mongodb.CryptoChange
+	err     error // returned after the changes, instead of blocking
+	current int
+}
+
+func (s *mockStream) Next(ctx context.Context) bool {
+	if s.current < len(s.changes) {
+		s.current++
+		return true
+	}
+	if s.err == nil {
+		<-ctx.Done()
+	}
+	return false
+}
+
+func (s *mockStream) Decode(val interface{}) error {
+	*val.(*mongodb.CryptoChange) = s.changes[s.current-1]
+	return nil
+}
+
+func (s *mockStream) ResumeToken() bson.Raw {
+	token, _ := bson.Marshal(bson.M{"_data": s.current})
+	return token
+}
+
+func (s *mockStream) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return context.Canceled
+}
+
+func (s *mockStream) Close(ctx context.Context) error {
+	return nil
+}
+
+// Resume tokens saved by the watcher, by its id
+type mockTokens struct {
+	mu     sync.Mutex
+	tokens map[string]bson.Raw
+	opened []bson.Raw // token of each WatchCryptos
+}
+
+func useMocks(streams ...*mockStream) *mockTokens {
+	saved := &mockTokens{tokens: map[string]bson.Raw{}}
+
+	mongodb.GetResumeToken = func(ctx context.Context, coll mongodb.IMCollection, watcherId string) (bson.Raw, error) {
+		saved.mu.Lock()
+		defer saved.mu.Unlock()
+		return saved.tokens[watcherId], nil
+	}
+
+	mongodb.SaveResumeToken = func(ctx context.Context, coll mongodb.IMCollection, watcherId string, token bson.Raw) error {
+		saved.mu.Lock()
+		defer saved.mu.Unlock()
+		saved.tokens[watcherId] = token
+		return nil
+	}
+
+	mongodb.WatchCryptos = func(ctx context.Context, coll *mongo.Collection, token bson.Raw) (mongodb.ChangeStream, error) {
+		saved.mu.Lock()
+		defer saved.mu.Unlock()
+		saved.opened = append(saved.opened, token)
+		if len(streams) == 0 {
+			return nil, errors.New("no replica set")
+		}
+		stream := streams[0]
+		if len(streams) > 1 {
+			streams = streams[1:]
+		}
+		return stream, nil
+	}
+
+	return saved
+}
+
+func (m *mockTokens) get(id string) bson.Raw {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tokens[id]
+}
+
+func (m *mockTokens) openedCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.opened)
+}
+
+func changeOf(operation string, id primitive.ObjectID) mongodb.CryptoChange {
+	change := mongodb.CryptoChange{OperationType: operation}
+	change.DocumentKey.Id = id
+	return change
+}
+
+func testConfig() Config {
+	return Config{
+		Enabled:  true,
+		Id:       "replica-1",
+		RetryMin: helpers.Duration{Duration: time.Millisecond},
+		RetryMax: helpers.Duration{Duration: 5 * time.Millisecond},
+	}
+}
+
+// Help function to collect the changes handled
+type handled struct {
+	mu      sync.Mutex
+	changes []mongodb.CryptoChange
+}
+
+func (h *handled) handle(ctx context.Context, change mongodb.CryptoChange) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.changes = append(h.changes, change)
+}
+
+func (h *handled) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.changes)
+}
+
+// Testing changes are handled in order and the token of last one is saved
+func TestWatcherHandlesChangesAndSavesToken(t *testing.T) {
+	first, second := primitive.NewObjectID(), primitive.NewObjectID()
+	stream := &mockStream{changes: []mongodb.CryptoChange{changeOf("update", first), changeOf("delete", second)}}
+	tokens := useMocks(stream)
+	changes := &handled{}
+
+	w := New(testConfig(), nil, nil, changes.handle, nil)
+	w.Start()
+	require.Eventually(t, func() bool { return changes.count() == 2 }, time.Second, time.Millisecond)
+	w.Stop()
+
+	require.Equal(t, first, changes.changes[0].DocumentKey.Id)
+	require.Equal(t, "delete", changes.changes[1].OperationType)
+	require.Equal(t, stream.ResumeToken(), tokens.get("replica-1"))
+}
+
+// Testing the stream is opened after the saved token of its replica
+func TestWatcherResumesFromSavedToken(t *testing.T) {
+	tokens := useMocks(&mockStream{})
+	saved, _ := bson.Marshal(bson.M{"_data": "82635"})
+	tokens.tokens["replica-1"] = saved
+	tokens.tokens["replica-2"], _ = bson.Marshal(bson.M{"_data": "other"})
+
+	w := New(testConfig(), nil, nil, (&handled{}).handle, nil)
+	w.Start()
+	require.Eventually(t, func() bool { return tokens.openedCount() == 1 }, time.Second, time.Millisecond)
+	w.Stop()
+
+	require.Equal(t, bson.Raw(saved), tokens.opened[0])
+}
+
+// Testing the stream is opened again after an error, from the last token saved
+func TestWatcherReopensAfterError(t *testing.T) {
+	broken := &mockStream{changes: []mongodb.CryptoChange{changeOf("insert", primitive.NewObjectID())}, err: errors.New("connection reset")}
+	tokens := useMocks(broken, &mockStream{})
+	changes := &handled{}
+
+	w := New(testConfig(), nil, nil, changes.handle, nil)
+	w.Start()
+	require.Eventually(t, func() bool { return tokens.openedCount() == 2 }, time.Second, time.Millisecond)
+	w.Stop()
+
+	require.Equal(t, 1, changes.count())
+	require.Nil(t, tokens.opened[0])
+	require.Equal(t, broken.ResumeToken(), tokens.opened[1])
+}
+
+// Testing a token not in oplog is removed and the stream starts from now
+func TestWatcherWithResumeTokenLost(t *testing.T) {
+	lost := &mockStream{err: mongo.CommandError{Code: 286, Message: "resume point may no longer be in the oplog"}}
+	tokens := useMocks(lost, &mockStream{})
+	tokens.tokens["replica-1"], _ = bson.Marshal(bson.M{"_data": "old"})
+
+	w := New(testConfig(), nil, nil, (&handled{}).handle, nil)
+	w.Start()
+	require.Eventually(t, func() bool { return tokens.openedCount() == 2 }, time.Second, time.Millisecond)
+	w.Stop()
+
+	require.NotNil(t, tokens.opened[0])
+	require.Nil(t, tokens.opened[1])
+}
+
+// Testing invalidate is not handled as a change of crypto and the stream starts from now
+func TestWatcherWithInvalidate(t *testing.T) {
+	invalidated := &mockStream{changes: []mongodb.CryptoChange{changeOf("update", primitive.NewObjectID()), {OperationType: "invalidate"}}}
+	tokens := useMocks(invalidated, &mockStream{})
+	changes := &handled{}
+
+	w := New(testConfig(), nil, nil, changes.handle, nil)
+	w.Start()
+	require.Eventually(t, func() bool { return tokens.openedCount() == 2 }, time.Second, time.Millisecond)
+	w.Stop()
+
+	require.Equal(t, 1, changes.count())
+	require.Nil(t, tokens.opened[1])
+}
+
+// Testing the watcher keeps retrying while the stream can not be opened and stops with Stop
+func TestWatcherRetriesUntilStop(t *testing.T) {
+	tokens := useMocks()
+
+	w := New(testConfig(), nil, nil, (&handled{}).handle, nil)
+	w.Start()
+	require.Eventually(t, func() bool { return tokens.openedCount() >= 3 }, time.Second, time.Millisecond)
+	w.Stop()
+}
+
+// Testing the state is open only while a stream is open, never when it can not be opened
+func TestWatcherState(t *testing.T) {
+	var mu sync.Mutex
+	states := []bool{}
+	state := func(open bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		states = append(states, open)
+	}
+	get := func() []bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]bool{}, states...)
+	}
+
+	useMocks()
+	w := New(testConfig(), nil, nil, (&handled{}).handle, state)
+	w.Start()
+	time.Sleep(20 * time.Millisecond)
+	w.Stop()
+	require.Empty(t, get())
+
+	broken := &mockStream{err: errors.New("connection reset")}
+	tokens := useMocks(broken, &mockStream{})
+	w = New(testConfig(), nil, nil, (&handled{}).handle, state)
+	w.Start()
+	require.Eventually(t, func() bool { return tokens.openedCount() == 2 && len(get()) == 3 }, time.Second, time.Millisecond)
+	w.Stop()
+
+	require.Equal(t, []bool{true, false, true, false}, get())
+}
+
+// Testing config requires id and a valid retry
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("WATCHER_ID", "replica-1")
+	cfg, err := LoadConfig()
+	require.Nil(t, err)
+	require.Equal(t, "replica-1", cfg.Id)
+	require.Equal(t, time.Second, cfg.RetryMin.Duration)
+
+	t.Setenv("WATCHER_RETRY_MIN", "1m")
+	_, err = LoadConfig()
+	require.NotNil(t, err)
+}